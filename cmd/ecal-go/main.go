@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Blutkoete/golang-ecal/ecal"
+)
+
+const usage = `Usage: ecal-go <command> [arguments]
+
+Commands:
+    topic list              list all topics
+    topic info <name>       show type, description, publishers and subscribers of a topic
+    topic echo <name>       print the messages of a topic
+    topic hz <name>         print rate, jitter and bandwidth of a topic
+
+Run "ecal-go topic <command> -h" for the options of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("ecal-go: ")
+
+	if len(os.Args) < 3 || os.Args[1] != "topic" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var run func(args []string) error
+	switch os.Args[2] {
+	case "list":
+		run = topicList
+	case "info":
+		run = topicInfo
+	case "echo":
+		run = topicEcho
+	case "hz":
+		run = topicHz
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command \"topic %s\".\n\n", os.Args[2])
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	err := ecal.Initialize(os.Args, "ecal-go", ecal.InitDefault|ecal.InitMonitoring)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = run(os.Args[3:])
+	ecal.Finalize(ecal.InitAll)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
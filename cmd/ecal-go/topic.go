@@ -0,0 +1,446 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Blutkoete/golang-ecal/ecal"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// defaultWait is how long the registrations are collected before topics are listed. eCAL registers every second.
+const defaultWait = 2 * time.Second
+
+// newFlagSet creates the flags of a command, printing the usage line followed by the options on errors.
+func newFlagSet(name string, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ecal-go %s [options] %s\n\nOptions:\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseTopicArgs parses the flags of a command taking a topic name. Flags are accepted before and after the name.
+func parseTopicArgs(flags *flag.FlagSet, args []string) (string, error) {
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return "", errors.New("no topic name given")
+	}
+
+	topicName := flags.Arg(0)
+	flags.Parse(flags.Args()[1:])
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return "", errors.New("too many arguments")
+	}
+	return topicName, nil
+}
+
+// interruptContext returns a context that is cancelled on an interrupt or when eCAL shuts the process down.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := ecal.ShutdownContext(context.Background())
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+func monitoredTopics(wait time.Duration) ([]ecal.MonitoredTopic, error) {
+	time.Sleep(wait)
+
+	snapshot, err := ecal.Monitoring()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Topics, nil
+}
+
+func topicList(args []string) error {
+	flags := newFlagSet("topic list", "")
+	wait := flags.Duration("wait", defaultWait, "time to collect registrations")
+	flags.Parse(args)
+
+	topics, err := monitoredTopics(*wait)
+	if err != nil {
+		return err
+	}
+
+	type topicSummary struct {
+		topicType   string
+		publishers  int
+		subscribers int
+	}
+	summaries := make(map[string]*topicSummary)
+	for _, topic := range topics {
+		summary, ok := summaries[topic.TopicName]
+		if !ok {
+			summary = &topicSummary{}
+			summaries[topic.TopicName] = summary
+		}
+
+		if summary.topicType == "" {
+			summary.topicType = topic.TopicType
+		}
+		switch topic.Direction {
+		case ecal.TopicDirectionPublisher:
+			summary.publishers++
+		case ecal.TopicDirectionSubscriber:
+			summary.subscribers++
+		}
+	}
+
+	names := make([]string, 0, len(summaries))
+	for name := range summaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tTYPE\tPUBLISHERS\tSUBSCRIBERS")
+	for _, name := range names {
+		summary := summaries[name]
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\n", name, summary.topicType, summary.publishers, summary.subscribers)
+	}
+	return writer.Flush()
+}
+
+func topicInfo(args []string) error {
+	flags := newFlagSet("topic info", "<name>")
+	wait := flags.Duration("wait", defaultWait, "time to collect registrations")
+	topicName, err := parseTopicArgs(flags, args)
+	if err != nil {
+		return err
+	}
+
+	topics, err := monitoredTopics(*wait)
+	if err != nil {
+		return err
+	}
+
+	publishers := make([]ecal.MonitoredTopic, 0)
+	subscribers := make([]ecal.MonitoredTopic, 0)
+	topicType := ""
+	topicDesc := ""
+	for _, topic := range topics {
+		if topic.TopicName != topicName {
+			continue
+		}
+
+		if topic.Direction == ecal.TopicDirectionPublisher {
+			publishers = append(publishers, topic)
+		} else {
+			subscribers = append(subscribers, topic)
+		}
+		// Publishers define the topic, so their type and description are preferred.
+		if topicType == "" || topic.Direction == ecal.TopicDirectionPublisher && topic.TopicType != "" {
+			topicType = topic.TopicType
+		}
+		if topicDesc == "" || topic.Direction == ecal.TopicDirectionPublisher && topic.TopicDescription != "" {
+			topicDesc = topic.TopicDescription
+		}
+	}
+	if len(publishers) == 0 && len(subscribers) == 0 {
+		return fmt.Errorf("topic %s not found", topicName)
+	}
+
+	fmt.Printf("Name:        %s\n", topicName)
+	fmt.Printf("Type:        %s\n", topicType)
+	fmt.Printf("Description: %s\n", describe(topicType, topicDesc))
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, group := range []struct {
+		title  string
+		topics []ecal.MonitoredTopic
+	}{{"Publishers", publishers}, {"Subscribers", subscribers}} {
+		fmt.Fprintf(writer, "\n%s: %d\n", group.title, len(group.topics))
+		if len(group.topics) == 0 {
+			continue
+		}
+
+		fmt.Fprintln(writer, "  HOST\tPROCESS\tPID\tUNIT\tFREQUENCY\tSIZE\tCONNECTIONS\tDROPS")
+		for _, topic := range group.topics {
+			fmt.Fprintf(writer, "  %s\t%s\t%d\t%s\t%.2f Hz\t%s\t%d/%d\t%d\n", topic.HostName, topic.ProcessName,
+				topic.ProcessID, topic.UnitName, topic.DataFrequency, formatBytes(float64(topic.TopicSize)),
+				topic.ConnectionsLocal, topic.ConnectionsExternal, topic.MessageDrops)
+		}
+	}
+	return writer.Flush()
+}
+
+// describe returns a printable form of the topic description: The fields of a protobuf message, the description
+// itself if it is text, or its size otherwise.
+func describe(topicType string, topicDesc string) string {
+	if topicDesc == "" {
+		return "-"
+	}
+
+	if strings.HasPrefix(topicType, "proto:") {
+		descriptor, err := ecal.ProtoMessageDescriptor(topicType, topicDesc)
+		if err == nil {
+			return describeMessage(descriptor)
+		}
+	}
+
+	printable := utf8.ValidString(topicDesc) && strings.IndexFunc(topicDesc, func(r rune) bool {
+		return !unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}) < 0
+	if printable {
+		return topicDesc
+	}
+	return fmt.Sprintf("%d bytes", len(topicDesc))
+}
+
+func describeMessage(descriptor protoreflect.MessageDescriptor) string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "message %s", descriptor.FullName())
+
+	fields := descriptor.Fields()
+	for idx := 0; idx < fields.Len(); idx++ {
+		field := fields.Get(idx)
+
+		fieldType := field.Kind().String()
+		switch {
+		case field.IsMap():
+			fieldType = fmt.Sprintf("map<%s, %s>", kindName(field.MapKey()), kindName(field.MapValue()))
+		case field.Message() != nil || field.Enum() != nil:
+			fieldType = kindName(field)
+		}
+		if field.IsList() {
+			fieldType = "repeated " + fieldType
+		}
+
+		fmt.Fprintf(builder, "\n    %d: %s %s", field.Number(), field.Name(), fieldType)
+	}
+	return builder.String()
+}
+
+func kindName(field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.Message() != nil:
+		return string(field.Message().FullName())
+	case field.Enum() != nil:
+		return string(field.Enum().FullName())
+	default:
+		return field.Kind().String()
+	}
+}
+
+func topicEcho(args []string) error {
+	flags := newFlagSet("topic echo", "<name>")
+	format := flags.String("format", "auto", "output format: raw, hex, string, proto, json or auto to choose by topic type")
+	count := flags.Int("n", 0, "number of messages to print, 0 for no limit")
+	topicName, err := parseTopicArgs(flags, args)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "auto", "raw", "hex", "string", "proto", "json":
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	sub, subChannel, err := ecal.SubscriberCreateAlloc(topicName, "", "", true, 0)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	decoder := ecal.NewProtoDecoder()
+	for printed := 0; *count <= 0 || printed < *count; printed++ {
+		var message ecal.Message
+		select {
+		case message = <-subChannel:
+		case <-ctx.Done():
+			return nil
+		}
+
+		if *format == "auto" {
+			*format = autoFormat(topicName)
+		}
+
+		err = printMessage(decoder, topicName, *format, message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// autoFormat chooses the output format by the topic type: protobuf messages are decoded, strings printed and
+// anything else dumped.
+func autoFormat(topicName string) string {
+	topicType, _ := ecal.TopicType(topicName)
+	switch {
+	case strings.HasPrefix(topicType, "proto:"):
+		return "proto"
+	case strings.HasPrefix(topicType, "base:std::string"):
+		return "string"
+	default:
+		return "hex"
+	}
+}
+
+func printMessage(decoder *ecal.ProtoDecoder, topicName string, format string, message ecal.Message) error {
+	switch format {
+	case "raw":
+		_, err := os.Stdout.Write(message.Content)
+		return err
+	case "hex":
+		fmt.Print(hex.Dump(message.Content))
+		fmt.Println("---")
+	case "string":
+		fmt.Println(string(message.Content))
+	case "proto", "json":
+		decoded, err := decoder.Decode(topicName, message)
+		if err != nil {
+			return err
+		}
+
+		var output []byte
+		if format == "json" {
+			output, err = decoded.JSON()
+		} else {
+			output, err = decoded.Text()
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(strings.TrimSuffix(string(output), "\n"))
+		if format == "proto" {
+			fmt.Println("---")
+		}
+	}
+	return nil
+}
+
+type sample struct {
+	received time.Time
+	size     int
+}
+
+func topicHz(args []string) error {
+	flags := newFlagSet("topic hz", "<name>")
+	window := flags.Int("window", 100, "number of messages the statistics are calculated for")
+	interval := flags.Duration("interval", time.Second, "time between two outputs")
+	topicName, err := parseTopicArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if *window < 2 {
+		return errors.New("window must be at least two messages")
+	}
+	if *interval <= 0 {
+		return errors.New("interval must be larger than zero")
+	}
+
+	sub, subChannel, err := ecal.SubscriberCreateAlloc(topicName, "", "", true, 0)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	samples := make([]sample, 0, *window)
+	received := 0
+	for {
+		select {
+		case message := <-subChannel:
+			if len(samples) == *window {
+				samples = samples[1:]
+			}
+			samples = append(samples, sample{received: time.Now(), size: len(message.Content)})
+			received++
+		case <-ticker.C:
+			if received == 0 {
+				fmt.Println("no new messages")
+				continue
+			}
+			received = 0
+			printStatistics(samples)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// printStatistics prints the rate, the spread of the periods between messages and the bandwidth of the samples.
+func printStatistics(samples []sample) {
+	if len(samples) < 2 {
+		fmt.Println("waiting for more messages")
+		return
+	}
+
+	periods := make([]float64, 0, len(samples)-1)
+	total := 0
+	for idx := 1; idx < len(samples); idx++ {
+		periods = append(periods, samples[idx].received.Sub(samples[idx-1].received).Seconds())
+		total += samples[idx].size
+	}
+
+	minPeriod, maxPeriod, sum := math.Inf(1), 0.0, 0.0
+	for _, period := range periods {
+		minPeriod = math.Min(minPeriod, period)
+		maxPeriod = math.Max(maxPeriod, period)
+		sum += period
+	}
+	mean := sum / float64(len(periods))
+
+	variance := 0.0
+	for _, period := range periods {
+		variance += (period - mean) * (period - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(periods)))
+
+	rate := 0.0
+	bandwidth := 0.0
+	if sum > 0 {
+		rate = float64(len(periods)) / sum
+		bandwidth = float64(total) / sum
+	}
+
+	fmt.Printf("rate: %.3f Hz  min: %s  max: %s  std dev: %s  bandwidth: %s/s  window: %d\n", rate,
+		seconds(minPeriod), seconds(maxPeriod), seconds(stdDev), formatBytes(bandwidth), len(samples))
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second)).Round(time.Microsecond)
+}
+
+func formatBytes(value float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB"}
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", value, units[unit])
+	}
+	return fmt.Sprintf("%.2f %s", value, units[unit])
+}
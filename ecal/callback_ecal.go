@@ -0,0 +1,90 @@
+//go:build !ecalfake
+
+package ecal
+
+/*
+#include <stdlib.h>
+#include <ecal/ecalc.h>
+
+extern int goServerMethodCallback(char*, char*, char*, char*, int, void**, int*, void*);
+extern void goClientResponseCallback(struct SServiceInfoC*, char*, int, void*);
+extern void goSubReceiveCallback(char*, struct SReceiveCallbackDataC*, void*);
+extern void goPubEventCallback(char*, struct SPubEventCallbackDataC*, void*);
+extern void goSubEventCallback(char*, struct SSubEventCallbackDataC*, void*);
+extern void goTimerCallback(void*);
+
+// eCAL copies the response of a method right after the callback returns on the same thread, so the response
+// of a call is freed when the next call on that thread starts. This keeps concurrent calls from freeing each
+// other's responses.
+static __thread void* serverResponse = NULL;
+
+static int serverMethodTrampoline(const char* method, const char* requestType, const char* responseType, const char* request, int requestLen, void** response, int* responseLen, void* par) {
+	free(serverResponse);
+	serverResponse = NULL;
+
+	int retState = goServerMethodCallback((char*)method, (char*)requestType, (char*)responseType, (char*)request, requestLen, response, responseLen, par);
+	serverResponse = *response;
+	return retState;
+}
+
+static MethodCallbackCT* serverMethodCallback() {
+	static MethodCallbackCT callback = serverMethodTrampoline;
+	return &callback;
+}
+
+static ResponseCallbackCT* clientResponseCallback() {
+	static ResponseCallbackCT callback = (ResponseCallbackCT)goClientResponseCallback;
+	return &callback;
+}
+
+static void* subReceiveCallback() {
+	return (void*)(ReceiveCallbackCT)goSubReceiveCallback;
+}
+
+static void* pubEventCallback() {
+	return (void*)(PubEventCallbackCT)goPubEventCallback;
+}
+
+static void* subEventCallback() {
+	return (void*)(SubEventCallbackCT)goSubEventCallback;
+}
+
+static void* timerCallback() {
+	return (void*)(TimerCallbackCT)goTimerCallback;
+}
+*/
+import "C"
+import (
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+)
+
+// The eCAL C interface expects plain C function pointers for its callbacks. The exported Go functions
+// are wrapped here as the definitions must not live in the same file as the //export directives.
+// Method and response callbacks are handed over by reference, all others by value.
+// The receive callback serves subscribers in callback mode and JSON subscribers alike, see messageReceiver.
+
+func serverMethodCallbackPtr() ecalc.MethodCallbackCT {
+	return ecalc.SwigcptrMethodCallbackCT(uintptr(unsafe.Pointer(C.serverMethodCallback())))
+}
+
+func clientResponseCallbackPtr() ecalc.ResponseCallbackCT {
+	return ecalc.SwigcptrResponseCallbackCT(uintptr(unsafe.Pointer(C.clientResponseCallback())))
+}
+
+func subReceiveCallbackPtr() *byte {
+	return (*byte)(C.subReceiveCallback())
+}
+
+func pubEventCallbackPtr() *byte {
+	return (*byte)(C.pubEventCallback())
+}
+
+func subEventCallbackPtr() *byte {
+	return (*byte)(C.subEventCallback())
+}
+
+func timerCallbackPtr() *byte {
+	return (*byte)(C.timerCallback())
+}
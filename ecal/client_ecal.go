@@ -0,0 +1,340 @@
+//go:build !ecalfake
+
+package ecal
+
+/*
+#include <stdlib.h>
+#include <ecal/ecalc.h>
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+	"github.com/mattn/go-pointer"
+)
+
+const (
+	CallStateNone     = iota
+	CallStateExecuted = iota
+	CallStateFailed   = iota
+)
+
+// Response is the answer of a single service server to a call.
+type Response struct {
+	HostName    string
+	ServiceName string
+	MethodName  string
+	Error       string
+	RetState    int
+	CallState   int
+	Content     []byte
+}
+
+// ClientIf calls the methods of service servers. The C interface has no call timeout, so a done context only ends
+// the wait for responses: The eCAL call keeps running in the background, later calls of the client wait for it to
+// return and its late responses are discarded. Each call owns the C memory it passes to eCAL until eCAL returns.
+type ClientIf interface {
+	Destroy() error
+
+	IsDestroyed() bool
+
+	GetHandle() uintptr
+	GetServiceName() string
+	GetHostName() string
+
+	SetHostName(hostName string) error
+
+	// Call calls the method on every matching server and collects all responses.
+	Call(ctx context.Context, method string, request []byte) ([]Response, error)
+	// CallAsync calls the method on every matching server. The responses are delivered on the returned
+	// channel, which is closed once all servers answered or the context is done.
+	CallAsync(ctx context.Context, method string, request []byte) (<-chan Response, error)
+	// CallWait calls the method on the first matching server on the given host and waits for its response.
+	CallWait(ctx context.Context, hostName string, method string, request []byte) (Response, error)
+}
+
+type pendingCall struct {
+	responseSink chan Response
+	done         chan struct{}
+}
+
+type client struct {
+	handle       uintptr
+	destroyed    bool
+	serviceName  string
+	hostName     string
+	reference    unsafe.Pointer
+	pending      *pendingCall
+	pendingMutex *sync.Mutex
+	callMutex    *sync.Mutex
+	mutex        *sync.Mutex
+}
+
+func (cl *client) Destroy() error {
+	cl.callMutex.Lock()
+	defer cl.callMutex.Unlock()
+
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	if cl.destroyed {
+		return errors.New("client already destroyed")
+	}
+
+	ecalc.ECAL_Client_RemResponseCallback(cl.handle)
+	pointer.Unref(cl.reference)
+	cl.reference = nil
+
+	rc := ecalc.ECAL_Client_Destroy(cl.handle)
+	if rc == 0 {
+		return errors.New("could not destroy client")
+	}
+
+	cl.destroyed = true
+	return nil
+}
+
+func (cl *client) IsDestroyed() bool {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	return cl.destroyed
+}
+
+func (cl *client) GetHandle() uintptr {
+	return cl.handle
+}
+
+func (cl *client) GetServiceName() string {
+	return cl.serviceName
+}
+
+func (cl *client) GetHostName() string {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	return cl.hostName
+}
+
+func (cl *client) SetHostName(hostName string) error {
+	cl.callMutex.Lock()
+	defer cl.callMutex.Unlock()
+
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	if cl.destroyed {
+		return errors.New("client already destroyed")
+	}
+
+	rc := ecalc.ECAL_Client_SetHostName(cl.handle, hostName)
+	if rc == 0 {
+		return errors.New("setting host name failed")
+	}
+
+	cl.hostName = hostName
+	return nil
+}
+
+func (cl *client) Call(ctx context.Context, method string, request []byte) ([]Response, error) {
+	responseSink, err := cl.CallAsync(ctx, method, request)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]Response, 0)
+	for response := range responseSink {
+		responses = append(responses, response)
+	}
+
+	if ctx.Err() != nil {
+		return responses, ctx.Err()
+	}
+	if len(responses) == 0 {
+		return responses, errors.New("no response received")
+	}
+
+	return responses, nil
+}
+
+func (cl *client) CallAsync(ctx context.Context, method string, request []byte) (<-chan Response, error) {
+	if cl.IsDestroyed() {
+		return nil, errors.New("client already destroyed")
+	}
+
+	responseSink := make(chan Response)
+	go func() {
+		// eCAL reports the responses of all calls of a client to a single callback, so calls are serialized.
+		cl.callMutex.Lock()
+		defer cl.callMutex.Unlock()
+
+		call := &pendingCall{responseSink: responseSink,
+			done: make(chan struct{})}
+		cl.pendingMutex.Lock()
+		cl.pending = call
+		cl.pendingMutex.Unlock()
+
+		finished := make(chan struct{})
+		go func() {
+			if !cl.IsDestroyed() {
+				ecalc.ECAL_Client_Call(cl.handle, method, string(request), len(request))
+			}
+			close(finished)
+		}()
+
+		select {
+		case <-finished:
+		case <-ctx.Done():
+		}
+
+		close(call.done)
+		cl.pendingMutex.Lock()
+		cl.pending = nil
+		close(responseSink)
+		cl.pendingMutex.Unlock()
+
+		// eCAL offers no way to abort a call, so the next one has to wait until this one returned.
+		<-finished
+	}()
+
+	return responseSink, nil
+}
+
+func (cl *client) CallWait(ctx context.Context, hostName string, method string, request []byte) (Response, error) {
+	if cl.IsDestroyed() {
+		return Response{}, errors.New("client already destroyed")
+	}
+
+	type result struct {
+		response Response
+		err      error
+	}
+	resultSink := make(chan result, 1)
+
+	go func() {
+		cl.callMutex.Lock()
+		defer cl.callMutex.Unlock()
+
+		if cl.IsDestroyed() {
+			resultSink <- result{err: errors.New("client already destroyed")}
+			return
+		}
+
+		cServiceInfo := (*C.struct_SServiceInfoC)(C.calloc(1, C.sizeof_struct_SServiceInfoC))
+		defer C.free(unsafe.Pointer(cServiceInfo))
+
+		var cResponse unsafe.Pointer
+		responseLen := ecalc.ECAL_Client_Call_Wait(cl.handle, hostName, method, string(request), len(request),
+			ecalc.SwigcptrStruct_SS_SServiceInfoC(uintptr(unsafe.Pointer(cServiceInfo))),
+			uintptr(unsafe.Pointer(&cResponse)), ecalc.ECAL_ALLOCATE_4ME)
+
+		// Call_Wait overwrites the host name of the client, so it is restored for subsequent calls.
+		ecalc.ECAL_Client_SetHostName(cl.handle, cl.GetHostName())
+
+		response := newResponse(cServiceInfo, nil, 0)
+		if cResponse != nil {
+			response.Content = C.GoBytes(cResponse, C.int(responseLen))
+			ecalc.ECAL_FreeMem(uintptr(cResponse))
+		}
+
+		if response.CallState != CallStateExecuted {
+			resultSink <- result{response: response, err: errors.New("call failed")}
+			return
+		}
+		resultSink <- result{response: response}
+	}()
+
+	select {
+	case result := <-resultSink:
+		return result.response, result.err
+	case <-ctx.Done():
+		return Response{}, ctx.Err()
+	}
+}
+
+func (cl *client) deliver(response Response) {
+	cl.pendingMutex.Lock()
+	defer cl.pendingMutex.Unlock()
+
+	if cl.pending == nil {
+		return
+	}
+
+	select {
+	case cl.pending.responseSink <- response:
+	case <-cl.pending.done:
+	}
+}
+
+func newResponse(cServiceInfo *C.struct_SServiceInfoC, cResponse *C.char, cResponseLen C.int) Response {
+	response := Response{}
+	if cServiceInfo != nil {
+		response.HostName = goStringOrEmpty(cServiceInfo.host_name)
+		response.ServiceName = goStringOrEmpty(cServiceInfo.service_name)
+		response.MethodName = goStringOrEmpty(cServiceInfo.method_name)
+		response.Error = goStringOrEmpty(cServiceInfo.error_msg)
+		response.RetState = int(cServiceInfo.ret_state)
+		response.CallState = int(cServiceInfo.call_state)
+	}
+
+	if cResponse != nil && cResponseLen > 0 {
+		response.Content = C.GoBytes(unsafe.Pointer(cResponse), cResponseLen)
+	}
+
+	return response
+}
+
+func goStringOrEmpty(cString *C.char) string {
+	if cString == nil {
+		return ""
+	}
+	return C.GoString(cString)
+}
+
+//export goClientResponseCallback
+func goClientResponseCallback(cServiceInfo *C.struct_SServiceInfoC, cResponse *C.char, cResponseLen C.int, par unsafe.Pointer) {
+	cl, ok := pointer.Restore(par).(*client)
+	if !ok {
+		return
+	}
+
+	cl.deliver(newResponse(cServiceInfo, cResponse, cResponseLen))
+}
+
+func ClientCreate(serviceName string) (ClientIf, error) {
+	if ecalc.ECAL_IsInitialized(InitService) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitService)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	handle := ecalc.ECAL_Client_Create(serviceName)
+	if handle == 0 {
+		return nil, errors.New("could not create new client")
+	}
+
+	cl := &client{handle: handle,
+		destroyed:    false,
+		serviceName:  serviceName,
+		hostName:     "",
+		pending:      nil,
+		pendingMutex: &sync.Mutex{},
+		callMutex:    &sync.Mutex{},
+		mutex:        &sync.Mutex{}}
+	cl.reference = pointer.Save(cl)
+
+	rc := ecalc.ECAL_Client_AddResponseCallbackC(handle, clientResponseCallbackPtr(), uintptr(cl.reference))
+	if rc == 0 {
+		pointer.Unref(cl.reference)
+		ecalc.ECAL_Client_Destroy(handle)
+		return nil, errors.New("adding response callback failed")
+	}
+
+	return cl, nil
+}
@@ -0,0 +1,110 @@
+package ecal
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the time for loops and timestamps, so nodes can follow the eCAL time when it is controlled
+// by a time master like a replay.
+type Clock interface {
+	Now() time.Time
+	Sleep(duration time.Duration)
+	After(duration time.Duration) <-chan time.Time
+	// NewTicker panics if the interval is not larger than zero, like time.NewTicker.
+	NewTicker(interval time.Duration) *Ticker
+}
+
+// Ticker delivers the time on C every interval like time.Ticker. Ticks are dropped for slow readers.
+type Ticker struct {
+	C    <-chan time.Time
+	stop func()
+}
+
+func (ticker *Ticker) Stop() {
+	ticker.stop()
+}
+
+// WallClock follows the system time.
+var WallClock Clock = wallClock{}
+
+// ECALClock follows the eCAL time. Sleeping cannot be interrupted, so a stopped ticker finishes its current
+// interval in the background.
+var ECALClock Clock = ecalClock{}
+
+type wallClock struct{}
+
+func (clock wallClock) Now() time.Time {
+	return time.Now()
+}
+
+func (clock wallClock) Sleep(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+func (clock wallClock) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
+
+func (clock wallClock) NewTicker(interval time.Duration) *Ticker {
+	ticker := time.NewTicker(interval)
+	return &Ticker{C: ticker.C, stop: ticker.Stop}
+}
+
+type ecalClock struct{}
+
+func (clock ecalClock) Now() time.Time {
+	return Now()
+}
+
+func (clock ecalClock) Sleep(duration time.Duration) {
+	Sleep(duration)
+}
+
+func (clock ecalClock) After(duration time.Duration) <-chan time.Time {
+	tickSink := make(chan time.Time, 1)
+	go func() {
+		Sleep(duration)
+		tickSink <- Now()
+	}()
+	return tickSink
+}
+
+func (clock ecalClock) NewTicker(interval time.Duration) *Ticker {
+	if interval <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	tickSink := make(chan time.Time, 1)
+	done := make(chan struct{})
+	once := &sync.Once{}
+
+	go func() {
+		next := Now().Add(interval)
+		for {
+			if wait := next.Sub(Now()); wait > 0 {
+				Sleep(wait)
+			}
+
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			now := Now()
+			select {
+			case tickSink <- now:
+			default:
+			}
+
+			// Intervals missed, e.g. after the time master jumped ahead, are skipped.
+			next = next.Add(interval)
+			if next.Before(now) {
+				next = now.Add(interval)
+			}
+		}
+	}()
+
+	return &Ticker{C: tickSink, stop: func() { once.Do(func() { close(done) }) }}
+}
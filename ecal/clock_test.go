@@ -0,0 +1,22 @@
+package ecal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTickerInterval(t *testing.T) {
+	clocks := map[string]Clock{"WallClock": WallClock, "ECALClock": ECALClock}
+	for name, clock := range clocks {
+		for _, interval := range []time.Duration{0, -time.Second} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s.NewTicker(%v) did not panic", name, interval)
+					}
+				}()
+				clock.NewTicker(interval).Stop()
+			}()
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package ecal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtoMessageDescriptor returns the descriptor of a protobuf topic from its type, e.g. "proto:pb.People.Person",
+// and its description, a serialized FileDescriptorSet.
+func ProtoMessageDescriptor(topicType string, topicDesc string) (protoreflect.MessageDescriptor, error) {
+	if !strings.HasPrefix(topicType, "proto:") {
+		return nil, fmt.Errorf("topic type %q is no protobuf type", topicType)
+	}
+
+	fileSet := &descriptorpb.FileDescriptorSet{}
+	err := proto.Unmarshal([]byte(topicDesc), fileSet)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := protodesc.NewFiles(fileSet)
+	if err != nil {
+		return nil, err
+	}
+
+	name := protoreflect.FullName(strings.TrimPrefix(topicType, "proto:"))
+	descriptor, err := files.FindDescriptorByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is no message", name)
+	}
+	return messageDescriptor, nil
+}
+
+// DynamicMessage is a protobuf message decoded without its compiled Go type.
+type DynamicMessage struct {
+	*dynamicpb.Message
+}
+
+// Field returns the value at a dot-separated path of field names, e.g. "position.x". Elements of repeated
+// fields are selected by their index, e.g. "points.2.x".
+func (message DynamicMessage) Field(path string) (protoreflect.Value, error) {
+	var value protoreflect.Value
+	var field protoreflect.FieldDescriptor
+	current := protoreflect.Message(message.Message)
+
+	names := strings.Split(path, ".")
+	for idx, name := range names {
+		if field != nil && field.IsList() {
+			list := value.List()
+			index, err := strconv.Atoi(name)
+			if err != nil || index < 0 || index >= list.Len() {
+				return protoreflect.Value{}, fmt.Errorf("invalid index %q for %s", name, field.FullName())
+			}
+
+			value = list.Get(index)
+			if field.Message() != nil {
+				current = value.Message()
+			} else if idx < len(names)-1 {
+				return protoreflect.Value{}, fmt.Errorf("element %d of %s has no fields", index, field.FullName())
+			}
+			field = nil
+			continue
+		}
+
+		if current == nil {
+			return protoreflect.Value{}, fmt.Errorf("%s has no field %s", field.FullName(), name)
+		}
+
+		fields := current.Descriptor().Fields()
+		next := fields.ByName(protoreflect.Name(name))
+		if next == nil {
+			next = fields.ByJSONName(name)
+		}
+		if next == nil {
+			return protoreflect.Value{}, fmt.Errorf("%s has no field %s", current.Descriptor().FullName(), name)
+		}
+
+		field = next
+		value = current.Get(field)
+		current = nil
+		if field.Message() != nil && !field.IsList() && !field.IsMap() {
+			current = value.Message()
+		}
+	}
+
+	return value, nil
+}
+
+func (message DynamicMessage) JSON() ([]byte, error) {
+	return protojson.Marshal(message.Message)
+}
+
+func (message DynamicMessage) Text() ([]byte, error) {
+	return prototext.MarshalOptions{Multiline: true}.Marshal(message.Message)
+}
+
+// ProtoDecoder decodes the messages of any protobuf topic using the descriptor shared by the publisher. The
+// descriptors are cached per topic.
+type ProtoDecoder struct {
+	descriptors map[string]protoreflect.MessageDescriptor
+	mutex       *sync.Mutex
+}
+
+func NewProtoDecoder() *ProtoDecoder {
+	return &ProtoDecoder{descriptors: make(map[string]protoreflect.MessageDescriptor),
+		mutex: &sync.Mutex{}}
+}
+
+// AddTopic sets the descriptor of a topic from its type and description instead of looking them up.
+func (decoder *ProtoDecoder) AddTopic(topicName string, topicType string, topicDesc string) error {
+	descriptor, err := ProtoMessageDescriptor(topicType, topicDesc)
+	if err != nil {
+		return err
+	}
+
+	decoder.mutex.Lock()
+	defer decoder.mutex.Unlock()
+
+	decoder.descriptors[topicName] = descriptor
+	return nil
+}
+
+// RemoveTopic drops the cached descriptor, so it is looked up again, e.g. after the topic type changed.
+func (decoder *ProtoDecoder) RemoveTopic(topicName string) {
+	decoder.mutex.Lock()
+	defer decoder.mutex.Unlock()
+
+	delete(decoder.descriptors, topicName)
+}
+
+// Descriptor returns the descriptor of a topic, looking up its type and description on first use.
+func (decoder *ProtoDecoder) Descriptor(topicName string) (protoreflect.MessageDescriptor, error) {
+	decoder.mutex.Lock()
+	descriptor, ok := decoder.descriptors[topicName]
+	decoder.mutex.Unlock()
+	if ok {
+		return descriptor, nil
+	}
+
+	topicType, err := TopicType(topicName)
+	if err != nil {
+		return nil, err
+	}
+	topicDesc, err := TopicDescription(topicName)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, err = ProtoMessageDescriptor(topicType, topicDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder.mutex.Lock()
+	defer decoder.mutex.Unlock()
+
+	decoder.descriptors[topicName] = descriptor
+	return descriptor, nil
+}
+
+func (decoder *ProtoDecoder) Decode(topicName string, message Message) (DynamicMessage, error) {
+	descriptor, err := decoder.Descriptor(topicName)
+	if err != nil {
+		return DynamicMessage{}, err
+	}
+
+	decoded := dynamicpb.NewMessage(descriptor)
+	err = proto.Unmarshal(message.Content, decoded)
+	if err != nil {
+		return DynamicMessage{}, err
+	}
+
+	return DynamicMessage{decoded}, nil
+}
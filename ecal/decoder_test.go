@@ -0,0 +1,151 @@
+package ecal
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// testRouteFile describes
+//
+//	message Point { double x = 1; double y = 2; }
+//	message Route { string name = 1; Point origin = 2; repeated Point points = 3; repeated int32 numbers = 4; }
+func testRouteFile() *descriptorpb.FileDescriptorProto {
+	field := func(name string, number int32, label descriptorpb.FieldDescriptorProto_Label,
+		fieldType descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		descriptor := &descriptorpb.FieldDescriptorProto{Name: proto.String(name),
+			Number: proto.Int32(number),
+			Label:  label.Enum(),
+			Type:   fieldType.Enum()}
+		if typeName != "" {
+			descriptor.TypeName = proto.String(typeName)
+		}
+		return descriptor
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	return &descriptorpb.FileDescriptorProto{Name: proto.String("route.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Point"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("x", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, ""),
+					field("y", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, ""),
+				}},
+			{Name: proto.String("Route"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("origin", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.Point"),
+					field("points", 3, repeated, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".test.Point"),
+					field("numbers", 4, repeated, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+				}},
+		}}
+}
+
+func testRouteDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	fileSet := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{testRouteFile()}}
+	topicDesc, err := proto.Marshal(fileSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	descriptor, err := ProtoMessageDescriptor("proto:test.Route", string(topicDesc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return descriptor
+}
+
+func TestProtoMessageDescriptor(t *testing.T) {
+	fileSet := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{testRouteFile()}}
+	topicDesc, err := proto.Marshal(fileSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		topicType string
+		valid     bool
+	}{
+		{"proto:test.Route", true},
+		{"proto:test.Point", true},
+		{"proto:test.Missing", false},
+		{"proto:test.Route.name", false},
+		{"base:std::string", false},
+	}
+
+	for _, test := range tests {
+		descriptor, err := ProtoMessageDescriptor(test.topicType, string(topicDesc))
+		if (err == nil) != test.valid {
+			t.Errorf("ProtoMessageDescriptor(%q) returned error %v", test.topicType, err)
+			continue
+		}
+		if test.valid && "proto:"+string(descriptor.FullName()) != test.topicType {
+			t.Errorf("ProtoMessageDescriptor(%q) returned %s", test.topicType, descriptor.FullName())
+		}
+	}
+}
+
+func TestDynamicMessageField(t *testing.T) {
+	descriptor := testRouteDescriptor(t)
+	pointDescriptor := descriptor.Fields().ByName("origin").Message()
+
+	point := func(x float64, y float64) protoreflect.Value {
+		message := dynamicpb.NewMessage(pointDescriptor)
+		message.Set(pointDescriptor.Fields().ByName("x"), protoreflect.ValueOfFloat64(x))
+		message.Set(pointDescriptor.Fields().ByName("y"), protoreflect.ValueOfFloat64(y))
+		return protoreflect.ValueOfMessage(message)
+	}
+
+	route := dynamicpb.NewMessage(descriptor)
+	fields := descriptor.Fields()
+	route.Set(fields.ByName("name"), protoreflect.ValueOfString("home"))
+	route.Set(fields.ByName("origin"), point(1, 2))
+	points := route.Mutable(fields.ByName("points")).List()
+	points.Append(point(3, 4))
+	points.Append(point(5, 6))
+	numbers := route.Mutable(fields.ByName("numbers")).List()
+	numbers.Append(protoreflect.ValueOfInt32(7))
+	numbers.Append(protoreflect.ValueOfInt32(8))
+	message := DynamicMessage{route}
+
+	tests := []struct {
+		path  string
+		value interface{}
+		valid bool
+	}{
+		{"name", "home", true},
+		{"origin.x", 1.0, true},
+		{"origin.y", 2.0, true},
+		{"points.0.x", 3.0, true},
+		{"points.1.y", 6.0, true},
+		{"numbers.0", int32(7), true},
+		{"numbers.1", int32(8), true},
+		{"missing", nil, false},
+		{"origin.z", nil, false},
+		{"name.length", nil, false},
+		{"points.2.x", nil, false},
+		{"points.-1.x", nil, false},
+		{"points.first.x", nil, false},
+		{"numbers.2", nil, false},
+		{"numbers.0.x", nil, false},
+	}
+
+	for _, test := range tests {
+		value, err := message.Field(test.path)
+		if (err == nil) != test.valid {
+			t.Errorf("Field(%q) returned error %v", test.path, err)
+			continue
+		}
+		if test.valid && value.Interface() != test.value {
+			t.Errorf("Field(%q) = %v, want %v", test.path, value.Interface(), test.value)
+		}
+	}
+}
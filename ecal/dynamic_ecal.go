@@ -0,0 +1,217 @@
+//go:build !ecalfake
+
+package ecal
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+	"github.com/mattn/go-pointer"
+)
+
+type jsonSubscriber struct {
+	handle     uintptr
+	rejected   int64
+	received   int64
+	running    bool
+	destroyed  bool
+	closed     bool
+	reference  unsafe.Pointer
+	workers    *sync.WaitGroup
+	outputSink chan Message
+	topicName  string
+	mutex      *sync.Mutex
+}
+
+func (sub *jsonSubscriber) Start() error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	if sub.running {
+		return nil
+	}
+
+	rc := ecalc.ECAL_Proto_Dyn_JSON_Sub_AddReceiveCallbackC(sub.handle, subReceiveCallbackPtr(), uintptr(sub.reference))
+	if rc == 0 {
+		return errors.New("adding receive callback failed")
+	}
+
+	sub.running = true
+	return nil
+}
+
+func (sub *jsonSubscriber) Stop() error {
+	sub.mutex.Lock()
+
+	if sub.destroyed {
+		sub.mutex.Unlock()
+		return errors.New("subscriber already destroyed")
+	}
+
+	if !sub.running {
+		sub.mutex.Unlock()
+		return nil
+	}
+
+	// The receive callback is removed without holding the mutex, as eCAL waits for a running callback to return.
+	sub.running = false
+	sub.mutex.Unlock()
+
+	rc := ecalc.ECAL_Proto_Dyn_JSON_Sub_RemReceiveCallback(sub.handle)
+	if rc == 0 {
+		return errors.New("removing receive callback failed")
+	}
+
+	return nil
+}
+
+func (sub *jsonSubscriber) Destroy() error {
+	if !sub.IsStopped() {
+		sub.Stop()
+	}
+
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	rc := ecalc.ECAL_Proto_Dyn_JSON_Sub_Destroy(sub.handle)
+	if rc == 0 {
+		return errors.New("could not destroy subscriber")
+	}
+
+	pointer.Unref(sub.reference)
+	sub.reference = nil
+
+	sub.destroyed = true
+	return nil
+}
+
+// Close stops the subscriber, waits for pending deliveries to finish, destroys it and closes the output channel.
+func (sub *jsonSubscriber) Close() error {
+	sub.mutex.Lock()
+	if sub.closed {
+		sub.mutex.Unlock()
+		return errors.New("subscriber already closed")
+	}
+	sub.closed = true
+	sub.mutex.Unlock()
+
+	if !sub.IsDestroyed() {
+		sub.Stop()
+	}
+	sub.workers.Wait()
+
+	var err error
+	if !sub.IsDestroyed() {
+		err = sub.Destroy()
+	}
+
+	close(sub.outputSink)
+	return err
+}
+
+func (sub *jsonSubscriber) IsStopped() bool {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	return !sub.running
+}
+
+func (sub *jsonSubscriber) IsDestroyed() bool {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	return sub.destroyed
+}
+
+func (sub *jsonSubscriber) GetHandle() uintptr {
+	return sub.handle
+}
+
+func (sub *jsonSubscriber) GetRejectedCount() int64 {
+	return atomic.LoadInt64(&sub.rejected)
+}
+
+func (sub *jsonSubscriber) GetLastReceiveTime() time.Time {
+	received := atomic.LoadInt64(&sub.received)
+	if received == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, received)
+}
+
+func (sub *jsonSubscriber) GetOutputChannel() <-chan Message {
+	return sub.outputSink
+}
+
+func (sub *jsonSubscriber) GetTopic() string {
+	return sub.topicName
+}
+
+// receive is called from the eCAL receive thread with the message converted to JSON.
+func (sub *jsonSubscriber) receive(message Message) {
+	atomic.StoreInt64(&sub.received, time.Now().UnixNano())
+
+	sub.mutex.Lock()
+	if !sub.running {
+		sub.mutex.Unlock()
+		return
+	}
+	sub.workers.Add(1)
+	sub.mutex.Unlock()
+	defer sub.workers.Done()
+
+	deliverMessage(sub.outputSink, message, &sub.rejected)
+}
+
+// DynamicJSONSubscriber creates a subscriber converting the messages of a protobuf topic to JSON using the
+// descriptor shared by the publisher, so no compiled message types are needed. Like a subscriber in callback
+// mode, it keeps up to callbackBufferSize messages for a slow reader and drops further messages.
+func DynamicJSONSubscriber(topicName string, start bool) (JSONSubscriberIf, <-chan Message, error) {
+	if ecalc.ECAL_IsInitialized(InitSubscriber) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitSubscriber)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	handle := ecalc.ECAL_Proto_Dyn_JSON_Sub_Create(topicName)
+	if handle == 0 {
+		return nil, nil, errors.New("could not create new subscriber")
+	}
+
+	sub := &jsonSubscriber{handle: handle,
+		rejected:   0,
+		received:   0,
+		running:    false,
+		destroyed:  false,
+		closed:     false,
+		reference:  nil,
+		workers:    &sync.WaitGroup{},
+		outputSink: make(chan Message, callbackBufferSize),
+		topicName:  topicName,
+		mutex:      &sync.Mutex{}}
+	sub.reference = pointer.Save(sub)
+
+	if start {
+		err := sub.Start()
+		if err != nil {
+			sub.Destroy()
+			return nil, nil, err
+		}
+	}
+
+	return sub, sub.GetOutputChannel(), nil
+}
@@ -0,0 +1,10 @@
+//go:build ecalfake
+
+package ecal
+
+import "errors"
+
+// DynamicJSONSubscriber is not available, as the in-memory backend has no protobuf converter.
+func DynamicJSONSubscriber(topicName string, start bool) (JSONSubscriberIf, <-chan Message, error) {
+	return nil, nil, errors.New("dynamic subscribers not supported by the in-memory backend")
+}
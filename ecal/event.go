@@ -0,0 +1,27 @@
+package ecal
+
+const (
+	EventNone         = iota
+	EventConnected    = iota
+	EventDisconnected = iota
+	EventDropped      = iota
+	EventTimeout      = iota
+	EventCorrupted    = iota
+)
+
+// eventBufferSize is the number of events kept for a slow reader. Further events are dropped until the
+// reader catches up, so the eCAL threads are never blocked by the event channel.
+const eventBufferSize = 16
+
+type Event struct {
+	Type  int
+	Time  int64
+	Clock int64
+}
+
+func deliverEvent(eventSink chan Event, event Event) {
+	select {
+	case eventSink <- event:
+	default:
+	}
+}
@@ -1,6 +1,179 @@
 package ecal
 
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// The Init constants of the single components are defined by the backend.
+
+const InitAll = InitPublisher | InitSubscriber | InitService | InitMonitoring | InitLogging | InitTimeSync | InitRPC | InitProcessReg
+
+const InitDefault = InitPublisher | InitSubscriber | InitService | InitLogging | InitTimeSync | InitProcessReg
+
 type Message struct {
 	Content   []byte
 	Timestamp int64
+	ID        int64
+	Clock     int64
+}
+
+// closeOnDone closes a publisher or subscriber once the context is done, unless it was closed before.
+func closeOnDone(ctx context.Context, closed <-chan struct{}, close func() error) {
+	select {
+	case <-ctx.Done():
+		close()
+	case <-closed:
+	}
+}
+
+type PublisherIf interface {
+	Start() error
+	Stop() error
+	Destroy() error
+	Close() error
+
+	IsStopped() bool
+	IsDestroyed() bool
+	IsSubscribed() bool
+
+	GetHandle() uintptr
+	GetInputChannel() chan<- Message
+	GetEventChannel() <-chan Event
+	GetErrorChannel() <-chan error
+	GetTopic() string
+	GetType() string
+	GetDescription() string
+	GetQoS() (WriterQOS, error)
+	GetLayerMode() (int, int)
+	GetMaxBandwidthUDP() int64
+	GetID() int64
+	GetClock() Clock
+
+	SetDescription(topicDesc string) error
+	SetQoS(qos WriterQOS) error
+	SetLayerMode(layerMode int, sendMode int) error
+	SetMaxBandwidthUDP(bandwidth int64) error
+	SetID(id int64) error
+	// SetClock makes the publisher stamp messages sent with a timestamp of -1 from the clock instead of
+	// leaving it to eCAL. A nil clock restores the default.
+	SetClock(clock Clock)
+
+	ShareType(state int) error
+	ShareDescription(state int) error
+
+	Dump() ([]byte, error)
+
+	// Send publishes the message synchronously and returns the number of bytes sent. The context is only
+	// checked before sending as eCAL offers no way to abort a send in progress. Unlike the input channel, Send
+	// does not need the publisher to be started.
+	Send(ctx context.Context, message Message) (int, error)
+
+	send(message Message) (int, error)
+}
+
+// SendError reports a message from the input channel that could not be sent.
+type SendError struct {
+	Message Message
+	Err     error
+}
+
+func (err *SendError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *SendError) Unwrap() error {
+	return err.Err
+}
+
+// errorBufferSize is the number of send errors kept for a slow reader. Further errors are dropped.
+const errorBufferSize = 16
+
+const (
+	ReceiveModePolling  = iota
+	ReceiveModeCallback = iota
+	ReceiveModeAlloc    = iota
+)
+
+// callbackBufferSize is the number of messages kept for a slow reader of a subscriber in callback mode. Further
+// messages are dropped and counted until the reader catches up, so the eCAL receive thread is never blocked by
+// the output channel.
+const callbackBufferSize = 64
+
+// deliverMessage hands a message to the output channel without blocking, counting it in dropped if the
+// channel is full.
+func deliverMessage(outputSink chan Message, message Message, dropped *int64) {
+	select {
+	case outputSink <- message:
+	default:
+		atomic.AddInt64(dropped, 1)
+	}
+}
+
+type SubscriberIf interface {
+	Start() error
+	Stop() error
+	Destroy() error
+	Close() error
+
+	IsStopped() bool
+	IsDestroyed() bool
+
+	GetHandle() uintptr
+	GetBufferSize() int
+	// GetRejectedCount returns the number of messages dropped for being larger than the buffer size or, in
+	// callback mode, for arriving while the output channel was full.
+	GetRejectedCount() int64
+	// GetLastReceiveTime returns when the last message was received, or the zero time if none was received.
+	GetLastReceiveTime() time.Time
+	GetOutputChannel() <-chan Message
+	GetEventChannel() <-chan Event
+	GetTopic() string
+	GetType() string
+	GetDescription() string
+	GetQoS() (ReaderQOS, error)
+	GetIDs() []int64
+	GetTimeout() int
+	GetReceiveMode() int
+
+	SetQoS(qos ReaderQOS) error
+	SetIDs(id []int64) error
+	SetTimeout(timeout int) error
+
+	Dump() ([]byte, error)
+}
+
+type TimerIf interface {
+	// Start calls the handler or delivers a tick every period, or only once after the period if oneShot is set.
+	// A running timer is restarted. Start and Destroy must not be called from the handler.
+	Start(period time.Duration, oneShot bool) error
+	// Stop may be called from the handler, no tick follows once it returns.
+	Stop() error
+	Destroy() error
+
+	IsStopped() bool
+	IsDestroyed() bool
+
+	GetHandle() uintptr
+	GetTickChannel() <-chan time.Time
+}
+
+// JSONSubscriberIf receives messages of any protobuf topic converted to JSON by eCAL. The content of each
+// message is the JSON document.
+type JSONSubscriberIf interface {
+	Start() error
+	Stop() error
+	Destroy() error
+	Close() error
+
+	IsStopped() bool
+	IsDestroyed() bool
+
+	GetHandle() uintptr
+	// GetRejectedCount returns the number of messages dropped for arriving while the output channel was full.
+	GetRejectedCount() int64
+	GetLastReceiveTime() time.Time
+	GetOutputChannel() <-chan Message
+	GetTopic() string
 }
@@ -0,0 +1,156 @@
+package ecal
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ProcessSeverityUnknown  = 0
+	ProcessSeverityHealthy  = 1
+	ProcessSeverityWarning  = 2
+	ProcessSeverityCritical = 3
+	ProcessSeverityFailed   = 4
+)
+
+const (
+	ProcessSeverityLevel1 = 1
+	ProcessSeverityLevel2 = 2
+	ProcessSeverityLevel3 = 3
+	ProcessSeverityLevel4 = 4
+	ProcessSeverityLevel5 = 5
+)
+
+// HealthCheck returns the severity of a single aspect of the process health and a description if it is not
+// healthy.
+type HealthCheck func() (int, string)
+
+// PublisherSubscribedCheck reports the given severity while the publisher has no subscribers.
+func PublisherSubscribedCheck(pub PublisherIf, severity int) HealthCheck {
+	return func() (int, string) {
+		if pub.IsSubscribed() {
+			return ProcessSeverityHealthy, ""
+		}
+		return severity, fmt.Sprintf("no subscribers for %s", pub.GetTopic())
+	}
+}
+
+// SubscriberReceivingCheck reports the given severity if the subscriber received no message within maxAge.
+func SubscriberReceivingCheck(sub SubscriberIf, maxAge time.Duration, severity int) HealthCheck {
+	return func() (int, string) {
+		lastReceived := sub.GetLastReceiveTime()
+		if lastReceived.IsZero() {
+			return severity, fmt.Sprintf("no data received on %s", sub.GetTopic())
+		}
+		if age := time.Since(lastReceived); age > maxAge {
+			return severity, fmt.Sprintf("no data received on %s for %s", sub.GetTopic(), age.Round(time.Millisecond))
+		}
+		return ProcessSeverityHealthy, ""
+	}
+}
+
+// HealthReporter periodically runs its checks and sets the process state to the worst severity found.
+type HealthReporter struct {
+	level    int
+	checks   map[string]HealthCheck
+	done     chan struct{}
+	finished chan struct{}
+	once     *sync.Once
+	mutex    *sync.Mutex
+}
+
+// NewHealthReporter creates a reporter setting the process state with the given severity level every interval.
+func NewHealthReporter(interval time.Duration, level int) (*HealthReporter, error) {
+	if interval <= 0 {
+		return nil, errors.New("interval must be larger than zero")
+	}
+
+	reporter := &HealthReporter{level: level,
+		checks:   make(map[string]HealthCheck),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+		once:     &sync.Once{},
+		mutex:    &sync.Mutex{}}
+
+	go reporter.run(interval)
+	return reporter, nil
+}
+
+func (reporter *HealthReporter) run(interval time.Duration) {
+	defer close(reporter.finished)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		reporter.Report()
+
+		select {
+		case <-ticker.C:
+		case <-reporter.done:
+			return
+		}
+	}
+}
+
+// AddCheck registers a check under the given name, replacing a check of the same name.
+func (reporter *HealthReporter) AddCheck(name string, check HealthCheck) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.checks[name] = check
+}
+
+func (reporter *HealthReporter) RemoveCheck(name string) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	delete(reporter.checks, name)
+}
+
+// Report runs all checks and sets the process state immediately. The info lists the failed checks by name. A
+// check reporting an unknown severity counts as a warning.
+func (reporter *HealthReporter) Report() {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	names := make([]string, 0, len(reporter.checks))
+	for name := range reporter.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	severity := ProcessSeverityHealthy
+	infos := make([]string, 0)
+	for _, name := range names {
+		checkSeverity, info := reporter.checks[name]()
+		if checkSeverity == ProcessSeverityHealthy {
+			continue
+		}
+		if checkSeverity == ProcessSeverityUnknown {
+			checkSeverity = ProcessSeverityWarning
+		}
+
+		if checkSeverity > severity {
+			severity = checkSeverity
+		}
+		infos = append(infos, fmt.Sprintf("%s: %s", name, info))
+	}
+
+	SetProcessState(severity, reporter.level, strings.Join(infos, "; "))
+}
+
+// Close stops reporting. The last reported state is kept.
+func (reporter *HealthReporter) Close() error {
+	err := errors.New("health reporter already closed")
+	reporter.once.Do(func() {
+		close(reporter.done)
+		<-reporter.finished
+		err = nil
+	})
+	return err
+}
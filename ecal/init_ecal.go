@@ -1,3 +1,5 @@
+//go:build !ecalfake
+
 package ecal
 
 import "C"
@@ -17,10 +19,6 @@ const InitTimeSync uint = uint(ecalc.ECAL_Init_TimeSync)
 const InitRPC uint = uint(ecalc.ECAL_Init_RPC)
 const InitProcessReg uint = uint(ecalc.ECAL_Init_ProcessReg)
 
-const InitAll = InitPublisher | InitSubscriber | InitService | InitMonitoring | InitLogging | InitTimeSync | InitRPC | InitProcessReg
-
-const InitDefault = InitPublisher | InitSubscriber | InitService | InitLogging | InitTimeSync | InitProcessReg
-
 func Initialize(args []string, unitName string, components uint) error {
 	cArgs := C.malloc(C.size_t(len(args)) * C.size_t(unsafe.Sizeof(uintptr(0))))
 	goArgs := (*[1<<30 - 1]*C.char)(cArgs)
@@ -0,0 +1,47 @@
+//go:build ecalfake
+
+package ecal
+
+import "errors"
+
+// The Init constants match the values of eCAL.
+const InitPublisher uint = 0x01
+const InitSubscriber uint = 0x02
+const InitService uint = 0x04
+const InitMonitoring uint = 0x08
+const InitLogging uint = 0x10
+const InitTimeSync uint = 0x20
+const InitRPC uint = 0x40
+const InitProcessReg uint = 0x80
+
+func Initialize(args []string, unitName string, components uint) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.initialized&components == components {
+		return errors.New("already initialized")
+	}
+	if registry.initialized == 0 {
+		registry.unitName = unitName
+	}
+	registry.initialized |= components
+	return nil
+}
+
+func Finalize(components uint) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.initialized&components == 0 {
+		return errors.New("already finalized")
+	}
+	registry.initialized &^= components
+	return nil
+}
+
+func Ok() bool {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	return registry.initialized != 0 && !registry.shutdown
+}
@@ -0,0 +1,286 @@
+package ecal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	LogLevelNone    = 0
+	LogLevelInfo    = 1
+	LogLevelWarning = 2
+	LogLevelError   = 4
+	LogLevelFatal   = 8
+	LogLevelDebug1  = 16
+	LogLevelDebug2  = 32
+	LogLevelDebug3  = 64
+	LogLevelDebug4  = 128
+	LogLevelAll     = 255
+)
+
+// SlogLevelFatal is the slog level mapped to LogLevelFatal, as slog has no level above error.
+const SlogLevelFatal = slog.LevelError + 4
+
+type LogMessage struct {
+	// Time is the time of the message in microseconds.
+	Time        int64
+	HostName    string
+	ProcessID   int
+	ProcessName string
+	UnitName    string
+	Level       int
+	Content     string
+}
+
+// decodeLogging decodes the log messages following eCAL's logging.proto.
+func decodeLogging(data []byte) ([]LogMessage, error) {
+	messages := make([]LogMessage, 0)
+	err := forEachField(data, func(field protoField) error {
+		if field.number != 1 {
+			return nil
+		}
+
+		message := LogMessage{}
+		err := forEachField(field.bytes, func(field protoField) error {
+			switch field.number {
+			case 1:
+				message.Time = int64(field.varint)
+			case 2:
+				message.HostName = string(field.bytes)
+			case 3:
+				message.ProcessID = int(int32(field.varint))
+			case 4:
+				message.ProcessName = string(field.bytes)
+			case 5:
+				message.UnitName = string(field.bytes)
+			case 6:
+				message.Level = int(int32(field.varint))
+			case 7:
+				message.Content = string(field.bytes)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, message)
+		return nil
+	})
+
+	return messages, err
+}
+
+// LogLevelFromSlog maps a slog level to the eCAL log level.
+func LogLevelFromSlog(level slog.Level) int {
+	switch {
+	case level >= SlogLevelFatal:
+		return LogLevelFatal
+	case level >= slog.LevelError:
+		return LogLevelError
+	case level >= slog.LevelWarn:
+		return LogLevelWarning
+	case level >= slog.LevelInfo:
+		return LogLevelInfo
+	case level >= slog.LevelDebug:
+		return LogLevelDebug1
+	case level >= slog.LevelDebug-1:
+		return LogLevelDebug2
+	case level >= slog.LevelDebug-2:
+		return LogLevelDebug3
+	default:
+		return LogLevelDebug4
+	}
+}
+
+// SlogLevelFromLog maps an eCAL log level to the slog level.
+func SlogLevelFromLog(level int) slog.Level {
+	switch {
+	case level&LogLevelFatal != 0:
+		return SlogLevelFatal
+	case level&LogLevelError != 0:
+		return slog.LevelError
+	case level&LogLevelWarning != 0:
+		return slog.LevelWarn
+	case level&LogLevelInfo != 0:
+		return slog.LevelInfo
+	case level&LogLevelDebug1 != 0:
+		return slog.LevelDebug
+	case level&LogLevelDebug2 != 0:
+		return slog.LevelDebug - 1
+	case level&LogLevelDebug3 != 0:
+		return slog.LevelDebug - 2
+	default:
+		return slog.LevelDebug - 3
+	}
+}
+
+// LogHandler is a slog.Handler forwarding records to eCAL logging. The message is followed by the attributes
+// formatted like slog.TextHandler does; time and level are left to eCAL.
+type LogHandler struct {
+	inner  slog.Handler
+	buffer *bytes.Buffer
+	mutex  *sync.Mutex
+}
+
+// NewLogHandler creates a handler forwarding to eCAL logging. The options are applied like for slog.TextHandler.
+func NewLogHandler(opts *slog.HandlerOptions) *LogHandler {
+	textOpts := slog.HandlerOptions{}
+	if opts != nil {
+		textOpts = *opts
+	}
+
+	replaceAttr := textOpts.ReplaceAttr
+	textOpts.ReplaceAttr = func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) == 0 && (attr.Key == slog.TimeKey || attr.Key == slog.LevelKey || attr.Key == slog.MessageKey) {
+			return slog.Attr{}
+		}
+		if replaceAttr != nil {
+			return replaceAttr(groups, attr)
+		}
+		return attr
+	}
+
+	buffer := &bytes.Buffer{}
+	return &LogHandler{inner: slog.NewTextHandler(buffer, &textOpts),
+		buffer: buffer,
+		mutex:  &sync.Mutex{}}
+}
+
+func (handler *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.inner.Enabled(ctx, level)
+}
+
+func (handler *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.buffer.Reset()
+	err := handler.inner.Handle(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	content := record.Message
+	attrs := strings.TrimSuffix(handler.buffer.String(), "\n")
+	if attrs != "" {
+		content += " " + attrs
+	}
+
+	return Log(LogLevelFromSlog(record.Level), content)
+}
+
+func (handler *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{inner: handler.inner.WithAttrs(attrs),
+		buffer: handler.buffer,
+		mutex:  handler.mutex}
+}
+
+func (handler *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{inner: handler.inner.WithGroup(name),
+		buffer: handler.buffer,
+		mutex:  handler.mutex}
+}
+
+// LogReader polls the eCAL log messages and passes them to a slog.Handler.
+type LogReader struct {
+	handler   slog.Handler
+	interval  time.Duration
+	errorSink chan error
+	done      chan struct{}
+	finished  chan struct{}
+	once      *sync.Once
+}
+
+// NewLogReader creates a reader passing the eCAL log messages to the handler every interval. The records carry
+// the attributes host, pid, process and unit of the logging process.
+func NewLogReader(handler slog.Handler, interval time.Duration) (*LogReader, error) {
+	if handler == nil {
+		return nil, errors.New("no handler given")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be larger than zero")
+	}
+
+	reader := &LogReader{handler: handler,
+		interval:  interval,
+		errorSink: make(chan error, errorBufferSize),
+		done:      make(chan struct{}),
+		finished:  make(chan struct{}),
+		once:      &sync.Once{}}
+
+	go reader.read()
+	return reader, nil
+}
+
+func (reader *LogReader) read() {
+	defer close(reader.finished)
+
+	ticker := time.NewTicker(reader.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-reader.done:
+			return
+		}
+
+		messages, err := Logging()
+		if err != nil {
+			reader.report(err)
+			continue
+		}
+
+		for _, message := range messages {
+			err = reader.handle(message)
+			if err != nil {
+				reader.report(err)
+			}
+		}
+	}
+}
+
+func (reader *LogReader) handle(message LogMessage) error {
+	ctx := context.Background()
+	level := SlogLevelFromLog(message.Level)
+	if !reader.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	record := slog.NewRecord(time.UnixMicro(message.Time), level, message.Content, 0)
+	record.AddAttrs(slog.String("host", message.HostName),
+		slog.Int("pid", message.ProcessID),
+		slog.String("process", message.ProcessName),
+		slog.String("unit", message.UnitName))
+	return reader.handler.Handle(ctx, record)
+}
+
+func (reader *LogReader) report(err error) {
+	select {
+	case reader.errorSink <- err:
+	default:
+	}
+}
+
+// GetErrorChannel returns the channel reporting failed polls and handler errors. Errors are dropped while the
+// channel is full.
+func (reader *LogReader) GetErrorChannel() <-chan error {
+	return reader.errorSink
+}
+
+// Close stops polling and waits for the messages of a running poll to be handled.
+func (reader *LogReader) Close() error {
+	err := errors.New("log reader already closed")
+	reader.once.Do(func() {
+		close(reader.done)
+		<-reader.finished
+		err = nil
+	})
+	return err
+}
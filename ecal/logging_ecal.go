@@ -0,0 +1,73 @@
+//go:build !ecalfake
+
+package ecal
+
+import "C"
+import (
+	"errors"
+	"os"
+	"sync"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+)
+
+// logMutex keeps the log level set for a message until it is logged, as eCAL logs with the current level.
+var logMutex = &sync.Mutex{}
+
+func SetLogLevel(level int) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	ecalc.ECAL_Logging_SetLogLevel(ecalc.ECAL_Logging_eLogLevel(level))
+}
+
+func GetLogLevel() int {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	return int(ecalc.ECAL_Logging_GetLogLevel())
+}
+
+// Log sends the message to eCAL logging with the given level. The log level set by SetLogLevel is kept.
+func Log(level int, message string) error {
+	if ecalc.ECAL_IsInitialized(InitLogging) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitLogging)
+		if err != nil {
+			return err
+		}
+	}
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	previous := ecalc.ECAL_Logging_GetLogLevel()
+	ecalc.ECAL_Logging_SetLogLevel(ecalc.ECAL_Logging_eLogLevel(level))
+	ecalc.ECAL_Logging_Log(message)
+	ecalc.ECAL_Logging_SetLogLevel(previous)
+	return nil
+}
+
+// Logging returns the log messages eCAL received since the last call.
+func Logging() ([]LogMessage, error) {
+	if ecalc.ECAL_IsInitialized(InitMonitoring) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitMonitoring)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var cBuffer unsafe.Pointer
+	bytesInLogging := ecalc.ECAL_Monitoring_GetLogging(uintptr(unsafe.Pointer(&cBuffer)), ecalc.ECAL_ALLOCATE_4ME)
+	if cBuffer == nil {
+		if bytesInLogging == 0 {
+			return make([]LogMessage, 0), nil
+		}
+		return nil, errors.New("getting logging failed")
+	}
+
+	data := C.GoBytes(cBuffer, C.int(bytesInLogging))
+	ecalc.ECAL_FreeMem(uintptr(cBuffer))
+
+	return decodeLogging(data)
+}
@@ -0,0 +1,49 @@
+//go:build ecalfake
+
+package ecal
+
+import "os"
+
+func SetLogLevel(level int) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.logLevel = level
+}
+
+func GetLogLevel() int {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	return registry.logLevel
+}
+
+// Log keeps the message in memory until it is returned by Logging.
+func Log(level int, message string) error {
+	hostName, _ := os.Hostname()
+	processName := ""
+	if len(os.Args) > 0 {
+		processName = os.Args[0]
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.logs = append(registry.logs, LogMessage{Time: memoryTime(),
+		HostName:    hostName,
+		ProcessID:   os.Getpid(),
+		ProcessName: processName,
+		UnitName:    registry.unitName,
+		Level:       level,
+		Content:     message})
+	return nil
+}
+
+func Logging() ([]LogMessage, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	logs := registry.logs
+	registry.logs = make([]LogMessage, 0)
+	return logs, nil
+}
@@ -0,0 +1,86 @@
+package ecal
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeLogging(t *testing.T) {
+	first := wireMessage{}.varint(1, 1700000000000000).
+		string(2, "host1").
+		varint(3, 1234).
+		string(4, "/usr/bin/node").
+		string(5, "node").
+		varint(6, LogLevelWarning).
+		string(7, "disk almost full")
+	second := wireMessage{}.varint(3, -1).
+		varint(6, LogLevelDebug2).
+		varint(8, 1).
+		string(7, "")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		messages []LogMessage
+		valid    bool
+	}{
+		{"empty", nil, []LogMessage{}, true},
+		{"messages", wireMessage{}.bytes(1, first).bytes(1, second),
+			[]LogMessage{{Time: 1700000000000000,
+				HostName:    "host1",
+				ProcessID:   1234,
+				ProcessName: "/usr/bin/node",
+				UnitName:    "node",
+				Level:       LogLevelWarning,
+				Content:     "disk almost full"},
+				{ProcessID: -1, Level: LogLevelDebug2}}, true},
+		{"unknown fields", wireMessage{}.varint(2, 1).string(3, "ignored").bytes(1, wireMessage{}.string(7, "kept")),
+			[]LogMessage{{Content: "kept"}}, true},
+		{"truncated", wireMessage{}.bytes(1, first)[:10], nil, false},
+		{"truncated nested", wireMessage{}.bytes(1, first[:len(first)-1]), nil, false},
+	}
+
+	for _, test := range tests {
+		messages, err := decodeLogging(test.data)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: decodeLogging returned error %v", test.name, err)
+			continue
+		}
+		if test.valid && !reflect.DeepEqual(messages, test.messages) {
+			t.Errorf("%s: decodeLogging returned\n%+v\nwant\n%+v", test.name, messages, test.messages)
+		}
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	tests := []struct {
+		logLevel  int
+		slogLevel slog.Level
+	}{
+		{LogLevelFatal, SlogLevelFatal},
+		{LogLevelError, slog.LevelError},
+		{LogLevelWarning, slog.LevelWarn},
+		{LogLevelInfo, slog.LevelInfo},
+		{LogLevelDebug1, slog.LevelDebug},
+		{LogLevelDebug2, slog.LevelDebug - 1},
+		{LogLevelDebug3, slog.LevelDebug - 2},
+		{LogLevelDebug4, slog.LevelDebug - 3},
+	}
+
+	for _, test := range tests {
+		if level := SlogLevelFromLog(test.logLevel); level != test.slogLevel {
+			t.Errorf("SlogLevelFromLog(%d) = %v, want %v", test.logLevel, level, test.slogLevel)
+		}
+		if level := LogLevelFromSlog(test.slogLevel); level != test.logLevel {
+			t.Errorf("LogLevelFromSlog(%v) = %d, want %d", test.slogLevel, level, test.logLevel)
+		}
+	}
+
+	if level := SlogLevelFromLog(LogLevelWarning | LogLevelDebug1); level != slog.LevelWarn {
+		t.Errorf("SlogLevelFromLog of combined levels = %v, want the most severe", level)
+	}
+	if level := LogLevelFromSlog(slog.LevelError + 1); level != LogLevelError {
+		t.Errorf("LogLevelFromSlog between levels = %d, want %d", level, LogLevelError)
+	}
+}
@@ -0,0 +1,358 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+const testTimeout = time.Second
+
+func receiveMessage(t *testing.T, subChannel <-chan Message) Message {
+	t.Helper()
+
+	select {
+	case message, ok := <-subChannel:
+		if !ok {
+			t.Fatal("output channel closed")
+		}
+		return message
+	case <-time.After(testTimeout):
+		t.Fatal("no message received")
+	}
+	return Message{}
+}
+
+func TestMemoryDelivery(t *testing.T) {
+	pub, _, err := PublisherCreate("memory_delivery", "base:std::string", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pub.Close()
+
+	sub, subChannel, err := SubscriberCreate("memory_delivery", "base:std::string", "", true, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	for idx := 0; idx < 3; idx++ {
+		content := fmt.Sprintf("message %d", idx)
+		_, err = pub.Send(context.Background(), Message{Content: []byte(content), Timestamp: 42})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		message := receiveMessage(t, subChannel)
+		if string(message.Content) != content {
+			t.Errorf("received %q, want %q", message.Content, content)
+		}
+		if message.Timestamp != 42 {
+			t.Errorf("received timestamp %d, want 42", message.Timestamp)
+		}
+		if message.Clock != int64(idx+1) {
+			t.Errorf("received clock %d, want %d", message.Clock, idx+1)
+		}
+	}
+}
+
+func TestMemoryInputChannel(t *testing.T) {
+	pub, pubChannel, err := PublisherCreate("memory_input", "", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pub.Close()
+
+	sub, subChannel, err := SubscriberCreateCallback("memory_input", "", "", true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	pubChannel <- Message{Content: []byte("hello"), Timestamp: -1}
+	message := receiveMessage(t, subChannel)
+	if string(message.Content) != "hello" {
+		t.Errorf("received %q, want %q", message.Content, "hello")
+	}
+	if message.Timestamp <= 0 {
+		t.Errorf("timestamp %d not stamped", message.Timestamp)
+	}
+}
+
+func TestTopicsMatch(t *testing.T) {
+	tests := []struct {
+		pubName string
+		pubType string
+		subName string
+		subType string
+		match   bool
+	}{
+		{"a", "base:std::string", "a", "base:std::string", true},
+		{"a", "base:std::string", "a", "", true},
+		{"a", "", "a", "proto:pb.People.Person", true},
+		{"a", "", "a", "", true},
+		{"a", "base:std::string", "a", "proto:pb.People.Person", false},
+		{"a", "base:std::string", "b", "base:std::string", false},
+		{"a", "", "A", "", false},
+	}
+
+	for _, test := range tests {
+		match := topicsMatch(test.pubName, test.pubType, test.subName, test.subType)
+		if match != test.match {
+			t.Errorf("topicsMatch(%q, %q, %q, %q) = %t, want %t", test.pubName, test.pubType, test.subName,
+				test.subType, match, test.match)
+		}
+	}
+}
+
+func TestMemoryTypeMismatch(t *testing.T) {
+	pub, _, err := PublisherCreate("memory_mismatch", "base:std::string", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pub.Close()
+
+	sub, subChannel, err := SubscriberCreate("memory_mismatch", "proto:pb.People.Person", "", true, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	if pub.IsSubscribed() {
+		t.Error("publisher subscribed by subscriber of another type")
+	}
+
+	_, err = pub.Send(context.Background(), Message{Content: []byte("hello"), Timestamp: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case message := <-subChannel:
+		t.Errorf("received %q from publisher of another type", message.Content)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMemoryHistoryOverflow(t *testing.T) {
+	pub, _, err := PublisherCreate("memory_overflow", "", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pub.Close()
+
+	sub, subChannel, err := SubscriberCreate("memory_overflow", "", "", true, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	const sent = memoryHistoryDepth + 4
+	for idx := 1; idx <= sent; idx++ {
+		_, err = pub.Send(context.Background(), Message{Content: []byte{byte(idx)}, Timestamp: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dropped := 0
+	timeout := time.After(testTimeout)
+	for dropped == 0 {
+		select {
+		case event := <-sub.GetEventChannel():
+			if event.Type == EventDropped {
+				dropped++
+			}
+		case <-timeout:
+			t.Fatal("no dropped event received")
+		}
+	}
+
+	// The newest messages are kept, so the last one received is the last one sent.
+	var last Message
+	for received := 0; received < sent; received++ {
+		select {
+		case last = <-subChannel:
+			continue
+		case <-time.After(50 * time.Millisecond):
+		}
+		break
+	}
+	if len(last.Content) != 1 || last.Content[0] != sent {
+		t.Errorf("last message %v, want [%d]", last.Content, sent)
+	}
+}
+
+func TestMemoryKeepAllHistory(t *testing.T) {
+	pub, _, err := PublisherCreate("memory_keep_all", "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pub.Close()
+	err = pub.SetQoS(WriterQOS{HistoryKind: KeepAllHistoryQOS, Reliability: ReliableReliability})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, subChannel, err := SubscriberCreate("memory_keep_all", "", "", false, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	err = sub.SetQoS(ReaderQOS{HistoryKind: KeepAllHistoryQOS, Reliability: ReliableReliability})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sub.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const sent = memoryHistoryDepth * 2
+	for idx := 1; idx <= sent; idx++ {
+		_, err = pub.Send(context.Background(), Message{Content: []byte{byte(idx)}, Timestamp: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for idx := 1; idx <= sent; idx++ {
+		message := receiveMessage(t, subChannel)
+		if message.Content[0] != byte(idx) {
+			t.Fatalf("received message %d, want %d", message.Content[0], idx)
+		}
+	}
+}
+
+func TestMemoryClose(t *testing.T) {
+	pub, _, err := PublisherCreate("memory_close", "", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, subChannel, err := SubscriberCreate("memory_close", "", "", true, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sub.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-subChannel; ok {
+		t.Error("output channel not closed")
+	}
+	if !sub.IsDestroyed() {
+		t.Error("subscriber not destroyed by Close")
+	}
+	if sub.Close() == nil {
+		t.Error("second Close of subscriber succeeded")
+	}
+
+	err = pub.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.IsDestroyed() {
+		t.Error("publisher not destroyed by Close")
+	}
+	if pub.Close() == nil {
+		t.Error("second Close of publisher succeeded")
+	}
+	if _, err = pub.Send(context.Background(), Message{Content: []byte("late"), Timestamp: -1}); err == nil {
+		t.Error("Send after Close succeeded")
+	}
+}
+
+func TestMemoryCreateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, subChannel, err := SubscriberCreateContext(ctx, "memory_context", "", "", true, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-subChannel:
+		if ok {
+			t.Error("message received on cancelled subscriber")
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("output channel not closed after the context was cancelled")
+	}
+	if sub.Close() == nil {
+		t.Error("Close after the context was cancelled succeeded")
+	}
+}
+
+func TestMemoryLogKeepsLevel(t *testing.T) {
+	SetLogLevel(LogLevelWarning)
+	defer SetLogLevel(LogLevelInfo)
+
+	err := Log(LogLevelDebug1, "debug message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level := GetLogLevel(); level != LogLevelWarning {
+		t.Errorf("log level %d after Log, want %d", level, LogLevelWarning)
+	}
+
+	logs, err := Logging()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].Level != LogLevelDebug1 || logs[0].Content != "debug message" {
+		t.Errorf("logging returned %+v", logs)
+	}
+}
+
+func TestMemoryCallbackOverflow(t *testing.T) {
+	pub, _, err := PublisherCreate("memory_callback_overflow", "", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pub.Close()
+	err = pub.SetQoS(WriterQOS{HistoryKind: KeepAllHistoryQOS, Reliability: ReliableReliability})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, subChannel, err := SubscriberCreateCallback("memory_callback_overflow", "", "", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	err = sub.SetQoS(ReaderQOS{HistoryKind: KeepAllHistoryQOS, Reliability: ReliableReliability})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sub.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nobody reads the output channel, so the messages beyond its capacity are dropped and counted.
+	const sent = callbackBufferSize + 10
+	for idx := 1; idx <= sent; idx++ {
+		_, err = pub.Send(context.Background(), Message{Content: []byte{byte(idx)}, Timestamp: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(testTimeout)
+	for sub.GetRejectedCount() < sent-callbackBufferSize && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if rejected := sub.GetRejectedCount(); rejected != sent-callbackBufferSize {
+		t.Errorf("rejected %d messages, want %d", rejected, sent-callbackBufferSize)
+	}
+
+	message := receiveMessage(t, subChannel)
+	if message.Content[0] != 1 {
+		t.Errorf("first message %d, want 1", message.Content[0])
+	}
+}
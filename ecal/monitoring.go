@@ -0,0 +1,322 @@
+package ecal
+
+import (
+	"errors"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	TopicDirectionPublisher  = "publisher"
+	TopicDirectionSubscriber = "subscriber"
+)
+
+type MonitoringSnapshot struct {
+	Hosts     []MonitoredHost
+	Processes []MonitoredProcess
+	Services  []MonitoredService
+	Topics    []MonitoredTopic
+}
+
+type MonitoredHost struct {
+	HostName string
+	OSName   string
+}
+
+type MonitoredProcess struct {
+	RegistrationClock int
+	HostName          string
+	ProcessID         int
+	ProcessName       string
+	UnitName          string
+	ProcessParameter  string
+	Memory            int64
+	CPU               float32
+	UserTime          float32
+	DataWrite         int64
+	DataRead          int64
+	Severity          int
+	SeverityLevel     int
+	StateInfo         string
+}
+
+type MonitoredService struct {
+	RegistrationClock int
+	HostName          string
+	ProcessName       string
+	UnitName          string
+	ProcessID         int
+	ServiceName       string
+	TCPPort           int
+	Methods           []MonitoredMethod
+}
+
+type MonitoredMethod struct {
+	MethodName   string
+	RequestType  string
+	ResponseType string
+	CallCount    int64
+}
+
+type MonitoredTopic struct {
+	RegistrationClock   int
+	HostName            string
+	ProcessID           int
+	ProcessName         string
+	UnitName            string
+	TopicID             string
+	TopicName           string
+	Direction           string
+	TopicType           string
+	TopicDescription    string
+	TopicSize           int
+	ConnectionsLocal    int
+	ConnectionsExternal int
+	MessageDrops        int
+	DataID              int64
+	DataClock           int64
+	// DataFrequency is the number of messages sent or received per second.
+	DataFrequency float64
+}
+
+// The monitoring data is decoded directly from the protobuf wire format following eCAL's monitoring.proto,
+// host.proto, process.proto, service.proto and topic.proto. Unknown fields are skipped.
+
+type protoField struct {
+	number protowire.Number
+	varint uint64
+	fixed  uint64
+	bytes  []byte
+}
+
+func forEachField(data []byte, handle func(field protoField) error) error {
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return errors.New("invalid monitoring data")
+		}
+		data = data[n:]
+
+		field := protoField{number: number}
+		switch wireType {
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var value uint32
+			value, n = protowire.ConsumeFixed32(data)
+			field.fixed = uint64(value)
+		case protowire.Fixed64Type:
+			field.fixed, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(number, wireType, data)
+		}
+		if n < 0 {
+			return errors.New("invalid monitoring data")
+		}
+		data = data[n:]
+
+		err := handle(field)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeMonitoring(data []byte) (MonitoringSnapshot, error) {
+	snapshot := MonitoringSnapshot{Hosts: make([]MonitoredHost, 0),
+		Processes: make([]MonitoredProcess, 0),
+		Services:  make([]MonitoredService, 0),
+		Topics:    make([]MonitoredTopic, 0)}
+
+	err := forEachField(data, func(field protoField) error {
+		switch field.number {
+		case 1:
+			host, err := decodeHost(field.bytes)
+			if err != nil {
+				return err
+			}
+			snapshot.Hosts = append(snapshot.Hosts, host)
+		case 2:
+			process, err := decodeProcess(field.bytes)
+			if err != nil {
+				return err
+			}
+			snapshot.Processes = append(snapshot.Processes, process)
+		case 3:
+			service, err := decodeService(field.bytes)
+			if err != nil {
+				return err
+			}
+			snapshot.Services = append(snapshot.Services, service)
+		case 4:
+			topic, err := decodeTopic(field.bytes)
+			if err != nil {
+				return err
+			}
+			snapshot.Topics = append(snapshot.Topics, topic)
+		}
+		return nil
+	})
+
+	return snapshot, err
+}
+
+func decodeHost(data []byte) (MonitoredHost, error) {
+	host := MonitoredHost{}
+	err := forEachField(data, func(field protoField) error {
+		switch field.number {
+		case 1:
+			host.HostName = string(field.bytes)
+		case 2:
+			return forEachField(field.bytes, func(field protoField) error {
+				if field.number == 1 {
+					host.OSName = string(field.bytes)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+
+	return host, err
+}
+
+func decodeProcess(data []byte) (MonitoredProcess, error) {
+	process := MonitoredProcess{}
+	err := forEachField(data, func(field protoField) error {
+		switch field.number {
+		case 1:
+			process.RegistrationClock = int(int32(field.varint))
+		case 2:
+			process.HostName = string(field.bytes)
+		case 3:
+			process.ProcessID = int(int32(field.varint))
+		case 4:
+			process.ProcessName = string(field.bytes)
+		case 5:
+			process.UnitName = string(field.bytes)
+		case 6:
+			process.ProcessParameter = string(field.bytes)
+		case 7:
+			process.Memory = int64(field.varint)
+		case 8:
+			process.CPU = math.Float32frombits(uint32(field.fixed))
+		case 9:
+			process.UserTime = math.Float32frombits(uint32(field.fixed))
+		case 10:
+			process.DataWrite = int64(field.varint)
+		case 11:
+			process.DataRead = int64(field.varint)
+		case 12:
+			return forEachField(field.bytes, func(field protoField) error {
+				switch field.number {
+				case 1:
+					process.Severity = int(field.varint)
+				case 3:
+					process.StateInfo = string(field.bytes)
+				case 4:
+					process.SeverityLevel = int(field.varint)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+
+	return process, err
+}
+
+func decodeService(data []byte) (MonitoredService, error) {
+	service := MonitoredService{Methods: make([]MonitoredMethod, 0)}
+	err := forEachField(data, func(field protoField) error {
+		switch field.number {
+		case 1:
+			service.RegistrationClock = int(int32(field.varint))
+		case 2:
+			service.HostName = string(field.bytes)
+		case 3:
+			service.ProcessName = string(field.bytes)
+		case 4:
+			service.UnitName = string(field.bytes)
+		case 5:
+			service.ProcessID = int(int32(field.varint))
+		case 6:
+			service.ServiceName = string(field.bytes)
+		case 7:
+			service.TCPPort = int(int32(field.varint))
+		case 8:
+			method := MonitoredMethod{}
+			err := forEachField(field.bytes, func(field protoField) error {
+				switch field.number {
+				case 1:
+					method.MethodName = string(field.bytes)
+				case 2:
+					method.RequestType = string(field.bytes)
+				case 3:
+					method.ResponseType = string(field.bytes)
+				case 4:
+					method.CallCount = int64(field.varint)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			service.Methods = append(service.Methods, method)
+		}
+		return nil
+	})
+
+	return service, err
+}
+
+func decodeTopic(data []byte) (MonitoredTopic, error) {
+	topic := MonitoredTopic{}
+	err := forEachField(data, func(field protoField) error {
+		switch field.number {
+		case 1:
+			topic.RegistrationClock = int(int32(field.varint))
+		case 3:
+			topic.HostName = string(field.bytes)
+		case 4:
+			topic.ProcessID = int(int32(field.varint))
+		case 5:
+			topic.ProcessName = string(field.bytes)
+		case 6:
+			topic.UnitName = string(field.bytes)
+		case 7:
+			topic.TopicID = string(field.bytes)
+		case 8:
+			topic.TopicName = string(field.bytes)
+		case 9:
+			topic.Direction = string(field.bytes)
+		case 10:
+			topic.TopicType = string(field.bytes)
+		case 11:
+			topic.TopicDescription = string(field.bytes)
+		case 14:
+			topic.TopicSize = int(int32(field.varint))
+		case 15:
+			topic.ConnectionsLocal = int(int32(field.varint))
+		case 16:
+			topic.ConnectionsExternal = int(int32(field.varint))
+		case 17:
+			topic.MessageDrops = int(int32(field.varint))
+		case 18:
+			topic.DataID = int64(field.varint)
+		case 19:
+			topic.DataClock = int64(field.varint)
+		case 20:
+			// eCAL reports the frequency in mHz.
+			topic.DataFrequency = float64(int32(field.varint)) / 1000
+		}
+		return nil
+	})
+
+	return topic, err
+}
@@ -0,0 +1,63 @@
+//go:build !ecalfake
+
+package ecal
+
+import "C"
+import (
+	"errors"
+	"os"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+)
+
+// Monitoring returns the current state of all hosts, processes, services and topics known to eCAL.
+func Monitoring() (MonitoringSnapshot, error) {
+	if ecalc.ECAL_IsInitialized(InitMonitoring) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitMonitoring)
+		if err != nil {
+			return MonitoringSnapshot{}, err
+		}
+	}
+
+	var cBuffer unsafe.Pointer
+	bytesInMonitoring := ecalc.ECAL_Monitoring_GetMonitoring(uintptr(unsafe.Pointer(&cBuffer)), ecalc.ECAL_ALLOCATE_4ME)
+	if cBuffer == nil {
+		return MonitoringSnapshot{}, errors.New("getting monitoring failed")
+	}
+
+	data := C.GoBytes(cBuffer, C.int(bytesInMonitoring))
+	ecalc.ECAL_FreeMem(uintptr(cBuffer))
+
+	return decodeMonitoring(data)
+}
+
+// SetMonitoringFilter limits the monitored topics to names matching the include and not matching the exclude
+// regular expression. If both are empty, the filter is disabled.
+func SetMonitoringFilter(include string, exclude string) error {
+	if ecalc.ECAL_IsInitialized(InitMonitoring) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitMonitoring)
+		if err != nil {
+			return err
+		}
+	}
+
+	if include == "" && exclude == "" {
+		if ecalc.ECAL_Monitoring_SetFilterState(0) != 0 {
+			return errors.New("disabling monitoring filter failed")
+		}
+		return nil
+	}
+
+	if ecalc.ECAL_Monitoring_SetInclFilter(include) != 0 {
+		return errors.New("setting include filter failed")
+	}
+	if ecalc.ECAL_Monitoring_SetExclFilter(exclude) != 0 {
+		return errors.New("setting exclude filter failed")
+	}
+	if ecalc.ECAL_Monitoring_SetFilterState(1) != 0 {
+		return errors.New("enabling monitoring filter failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// Monitoring returns the publishers and subscribers of the in-memory backend as topics of this process.
+func Monitoring() (MonitoringSnapshot, error) {
+	hostName, _ := os.Hostname()
+	processName := ""
+	if len(os.Args) > 0 {
+		processName = os.Args[0]
+	}
+
+	// The publishers are locked while sending to the registry, so they are collected before locking them.
+	registry.mutex.Lock()
+	unitName := registry.unitName
+	process := MonitoredProcess{HostName: hostName,
+		ProcessID:     os.Getpid(),
+		ProcessName:   processName,
+		UnitName:      unitName,
+		Severity:      registry.severity,
+		SeverityLevel: registry.level,
+		StateInfo:     registry.stateInfo}
+	publishers := make([]*memoryPublisher, 0, len(registry.publishers))
+	for pub := range registry.publishers {
+		if registry.monitored(pub.topicName) {
+			publishers = append(publishers, pub)
+		}
+	}
+	subscribers := make([]*memorySubscriber, 0, len(registry.subscribers))
+	for sub := range registry.subscribers {
+		if registry.monitored(sub.topicName) {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	processes := []MonitoredProcess{process}
+	for _, started := range registry.processes {
+		processes = append(processes, started)
+	}
+	registry.mutex.Unlock()
+
+	snapshot := MonitoringSnapshot{Hosts: []MonitoredHost{{HostName: hostName}},
+		Processes: processes,
+		Services:  make([]MonitoredService, 0),
+		Topics:    make([]MonitoredTopic, 0)}
+
+	for _, pub := range publishers {
+		pub.mutex.Lock()
+		topic := MonitoredTopic{HostName: hostName,
+			ProcessID:        os.Getpid(),
+			ProcessName:      processName,
+			UnitName:         unitName,
+			TopicID:          strconv.FormatUint(uint64(pub.handle), 10),
+			TopicName:        pub.topicName,
+			Direction:        TopicDirectionPublisher,
+			TopicType:        pub.topicType,
+			TopicDescription: pub.topicDesc,
+			DataID:           pub.id,
+			DataClock:        pub.dataClock}
+		pub.mutex.Unlock()
+		snapshot.Topics = append(snapshot.Topics, topic)
+	}
+
+	for _, sub := range subscribers {
+		sub.queueMutex.Lock()
+		topic := MonitoredTopic{HostName: hostName,
+			ProcessID:        os.Getpid(),
+			ProcessName:      processName,
+			UnitName:         unitName,
+			TopicID:          strconv.FormatUint(uint64(sub.handle), 10),
+			TopicName:        sub.topicName,
+			Direction:        TopicDirectionSubscriber,
+			TopicType:        sub.topicType,
+			TopicDescription: sub.topicDesc,
+			DataClock:        sub.clock}
+		sub.queueMutex.Unlock()
+		snapshot.Topics = append(snapshot.Topics, topic)
+	}
+
+	return snapshot, nil
+}
+
+func SetMonitoringFilter(include string, exclude string) error {
+	var includeExp, excludeExp *regexp.Regexp
+	var err error
+	if include != "" {
+		includeExp, err = regexp.Compile(include)
+		if err != nil {
+			return err
+		}
+	}
+	if exclude != "" {
+		excludeExp, err = regexp.Compile(exclude)
+		if err != nil {
+			return err
+		}
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.include = includeExp
+	registry.exclude = excludeExp
+	return nil
+}
+
+func (reg *memoryRegistry) monitored(topicName string) bool {
+	if reg.include != nil && !reg.include.MatchString(topicName) {
+		return false
+	}
+	return reg.exclude == nil || !reg.exclude.MatchString(topicName)
+}
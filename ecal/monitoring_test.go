@@ -0,0 +1,163 @@
+package ecal
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// wireMessage builds protobuf fixtures field by field.
+type wireMessage []byte
+
+func (message wireMessage) varint(number protowire.Number, value int64) wireMessage {
+	message = protowire.AppendTag(message, number, protowire.VarintType)
+	return protowire.AppendVarint(message, uint64(value))
+}
+
+func (message wireMessage) float(number protowire.Number, value float32) wireMessage {
+	message = protowire.AppendTag(message, number, protowire.Fixed32Type)
+	return protowire.AppendFixed32(message, math.Float32bits(value))
+}
+
+func (message wireMessage) double(number protowire.Number, value float64) wireMessage {
+	message = protowire.AppendTag(message, number, protowire.Fixed64Type)
+	return protowire.AppendFixed64(message, math.Float64bits(value))
+}
+
+func (message wireMessage) bytes(number protowire.Number, value []byte) wireMessage {
+	message = protowire.AppendTag(message, number, protowire.BytesType)
+	return protowire.AppendBytes(message, value)
+}
+
+func (message wireMessage) string(number protowire.Number, value string) wireMessage {
+	return message.bytes(number, []byte(value))
+}
+
+func TestDecodeMonitoring(t *testing.T) {
+	host := wireMessage{}.string(1, "host1").
+		bytes(2, wireMessage{}.string(1, "Linux"))
+	process := wireMessage{}.varint(1, 3).
+		string(2, "host1").
+		varint(3, 1234).
+		string(4, "/usr/bin/node").
+		string(5, "node").
+		string(6, "--verbose").
+		varint(7, 4096).
+		float(8, 1.5).
+		float(9, 0.25).
+		varint(10, 100).
+		varint(11, 200).
+		bytes(12, wireMessage{}.varint(1, ProcessSeverityWarning).
+			string(3, "degraded").
+			varint(4, ProcessSeverityLevel2))
+	service := wireMessage{}.varint(1, 5).
+		string(2, "host1").
+		string(3, "/usr/bin/node").
+		string(4, "node").
+		varint(5, 1234).
+		string(6, "mirror").
+		varint(7, 40000).
+		bytes(8, wireMessage{}.string(1, "echo").
+			string(2, "string").
+			string(3, "string").
+			varint(4, 7))
+	topic := wireMessage{}.varint(1, 2).
+		string(3, "host1").
+		varint(4, 1234).
+		string(5, "/usr/bin/node").
+		string(6, "node").
+		string(7, "42").
+		string(8, "chatter").
+		string(9, TopicDirectionPublisher).
+		string(10, "base:std::string").
+		string(11, "").
+		varint(14, 128).
+		varint(15, 1).
+		varint(16, 2).
+		varint(17, 3).
+		varint(18, 9).
+		varint(19, 10).
+		varint(20, 2500)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		snapshot MonitoringSnapshot
+		valid    bool
+	}{
+		{"empty", nil, MonitoringSnapshot{Hosts: []MonitoredHost{},
+			Processes: []MonitoredProcess{},
+			Services:  []MonitoredService{},
+			Topics:    []MonitoredTopic{}}, true},
+		{"complete", wireMessage{}.bytes(1, host).bytes(2, process).bytes(3, service).bytes(4, topic),
+			MonitoringSnapshot{Hosts: []MonitoredHost{{HostName: "host1", OSName: "Linux"}},
+				Processes: []MonitoredProcess{{RegistrationClock: 3,
+					HostName:         "host1",
+					ProcessID:        1234,
+					ProcessName:      "/usr/bin/node",
+					UnitName:         "node",
+					ProcessParameter: "--verbose",
+					Memory:           4096,
+					CPU:              1.5,
+					UserTime:         0.25,
+					DataWrite:        100,
+					DataRead:         200,
+					Severity:         ProcessSeverityWarning,
+					SeverityLevel:    ProcessSeverityLevel2,
+					StateInfo:        "degraded"}},
+				Services: []MonitoredService{{RegistrationClock: 5,
+					HostName:    "host1",
+					ProcessName: "/usr/bin/node",
+					UnitName:    "node",
+					ProcessID:   1234,
+					ServiceName: "mirror",
+					TCPPort:     40000,
+					Methods: []MonitoredMethod{{MethodName: "echo",
+						RequestType:  "string",
+						ResponseType: "string",
+						CallCount:    7}}}},
+				Topics: []MonitoredTopic{{RegistrationClock: 2,
+					HostName:            "host1",
+					ProcessID:           1234,
+					ProcessName:         "/usr/bin/node",
+					UnitName:            "node",
+					TopicID:             "42",
+					TopicName:           "chatter",
+					Direction:           TopicDirectionPublisher,
+					TopicType:           "base:std::string",
+					TopicSize:           128,
+					ConnectionsLocal:    1,
+					ConnectionsExternal: 2,
+					MessageDrops:        3,
+					DataID:              9,
+					DataClock:           10,
+					DataFrequency:       2.5}}}, true},
+		{"unknown fields", wireMessage{}.varint(9, 1).
+			bytes(1, wireMessage{}.string(1, "host2").double(7, 1).varint(8, 1)),
+			MonitoringSnapshot{Hosts: []MonitoredHost{{HostName: "host2"}},
+				Processes: []MonitoredProcess{},
+				Services:  []MonitoredService{},
+				Topics:    []MonitoredTopic{}}, true},
+		{"negative process id", wireMessage{}.bytes(2, wireMessage{}.varint(3, -1)),
+			MonitoringSnapshot{Hosts: []MonitoredHost{},
+				Processes: []MonitoredProcess{{ProcessID: -1}},
+				Services:  []MonitoredService{},
+				Topics:    []MonitoredTopic{}}, true},
+		{"truncated", wireMessage{}.bytes(1, host)[:5], MonitoringSnapshot{}, false},
+		{"truncated nested", wireMessage{}.bytes(4, topic[:len(topic)-1]), MonitoringSnapshot{}, false},
+		{"invalid tag", []byte{0x80}, MonitoringSnapshot{}, false},
+	}
+
+	for _, test := range tests {
+		snapshot, err := decodeMonitoring(test.data)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: decodeMonitoring returned error %v", test.name, err)
+			continue
+		}
+		if test.valid && !reflect.DeepEqual(snapshot, test.snapshot) {
+			t.Errorf("%s: decodeMonitoring returned\n%+v\nwant\n%+v", test.name, snapshot, test.snapshot)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package ecal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// namedEventWaitSlice is the longest single wait on a named event, as eCAL cannot interrupt a wait. It bounds how
+// late a cancelled context or a Close is noticed.
+const namedEventWaitSlice = 100 * time.Millisecond
+
+// NamedEvent is an event shared by all processes on this host opening the same name. Set wakes a single waiter,
+// or the next one if nobody is waiting.
+type NamedEvent struct {
+	name   string
+	handle uintptr
+	closed bool
+	// Waits hold mutex for reading, so Close waits for running waits to finish their current slice.
+	mutex *sync.RWMutex
+}
+
+// OpenNamedEvent opens the named event, creating it if no process opened it yet.
+func OpenNamedEvent(name string) (*NamedEvent, error) {
+	if name == "" {
+		return nil, errors.New("no event name given")
+	}
+
+	handle, err := openEvent(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NamedEvent{name: name,
+		handle: handle,
+		closed: false,
+		mutex:  &sync.RWMutex{}}, nil
+}
+
+func (event *NamedEvent) GetName() string {
+	return event.name
+}
+
+func (event *NamedEvent) Set() error {
+	event.mutex.RLock()
+	defer event.mutex.RUnlock()
+
+	if event.closed {
+		return errors.New("event already closed")
+	}
+
+	return setEvent(event.handle)
+}
+
+// Wait blocks until the event is set or the context is done. The deadline of the context is used as timeout.
+func (event *NamedEvent) Wait(ctx context.Context) error {
+	for {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		timeout := namedEventWaitSlice
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return context.DeadlineExceeded
+			}
+			if remaining < timeout {
+				timeout = remaining
+			}
+		}
+
+		set, err := event.wait(timeout)
+		if err != nil || set {
+			return err
+		}
+	}
+}
+
+func (event *NamedEvent) wait(timeout time.Duration) (bool, error) {
+	event.mutex.RLock()
+	defer event.mutex.RUnlock()
+
+	if event.closed {
+		return false, errors.New("event already closed")
+	}
+
+	timeoutMs := int64(timeout / time.Millisecond)
+	if timeoutMs < 1 {
+		timeoutMs = 1
+	}
+	return waitForEvent(event.handle, timeoutMs), nil
+}
+
+// Close closes the event. Running waits return an error after their current slice.
+func (event *NamedEvent) Close() error {
+	event.mutex.Lock()
+	defer event.mutex.Unlock()
+
+	if event.closed {
+		return errors.New("event already closed")
+	}
+
+	event.closed = true
+	return closeEvent(event.handle)
+}
@@ -0,0 +1,44 @@
+//go:build !ecalfake
+
+package ecal
+
+import (
+	"errors"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+)
+
+func openEvent(name string) (uintptr, error) {
+	handle := ecalc.ECAL_Event_gOpenEvent(name)
+	if handle == 0 {
+		return 0, errors.New("could not open event")
+	}
+	if ecalc.ECAL_Event_gEventIsValid(handle) == 0 {
+		ecalc.ECAL_Event_gCloseEvent(handle)
+		return 0, errors.New("could not open event")
+	}
+
+	return handle, nil
+}
+
+func setEvent(handle uintptr) error {
+	rc := ecalc.ECAL_Event_gSetEvent(handle)
+	if rc == 0 {
+		return errors.New("could not set event")
+	}
+
+	return nil
+}
+
+func waitForEvent(handle uintptr, timeoutMs int64) bool {
+	return ecalc.ECAL_Event_gWaitForEvent(handle, timeoutMs) != 0
+}
+
+func closeEvent(handle uintptr) error {
+	rc := ecalc.ECAL_Event_gCloseEvent(handle)
+	if rc == 0 {
+		return errors.New("could not close event")
+	}
+
+	return nil
+}
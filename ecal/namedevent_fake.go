@@ -0,0 +1,76 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"errors"
+	"time"
+)
+
+// openEvent opens an event shared by all events of the same name in this process.
+func openEvent(name string) (uintptr, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	signal, ok := registry.events[name]
+	if !ok {
+		signal = make(chan struct{}, 1)
+		registry.events[name] = signal
+	}
+
+	registry.handles++
+	registry.openEvents[registry.handles] = signal
+	return registry.handles, nil
+}
+
+func eventSignal(handle uintptr) (chan struct{}, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	signal, ok := registry.openEvents[handle]
+	if !ok {
+		return nil, errors.New("invalid event handle")
+	}
+	return signal, nil
+}
+
+func setEvent(handle uintptr) error {
+	signal, err := eventSignal(handle)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case signal <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func waitForEvent(handle uintptr, timeoutMs int64) bool {
+	signal, err := eventSignal(handle)
+	if err != nil {
+		return false
+	}
+
+	timer := time.NewTimer(time.Duration(timeoutMs) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-signal:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func closeEvent(handle uintptr) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, ok := registry.openEvents[handle]; !ok {
+		return errors.New("could not close event")
+	}
+	delete(registry.openEvents, handle)
+	return nil
+}
@@ -1,9 +1,35 @@
 package ecal
 
-import (
-	"github.com/Blutkoete/golang-ecal/ecalc"
+const (
+	StartModeNormal    = 0
+	StartModeHidden    = 1
+	StartModeMinimized = 2
+	StartModeMaximized = 3
 )
 
-func ProcessSleepMS(sleepTimeMs int64) {
-	ecalc.ECAL_Process_SleepMS(sleepTimeMs)
+type StartOptions struct {
+	// Mode is one of the StartMode constants and only used on Windows.
+	Mode          int
+	CreateConsole bool
+	// Block waits for the process to terminate.
+	Block bool
+}
+
+// ProcessDetails describes the running process as seen by eCAL. The clocks count the send, write and read
+// operations of all publishers and subscribers, the bytes their payload.
+type ProcessDetails struct {
+	HostName         string
+	HostID           int
+	UnitName         string
+	ProcessID        int
+	ProcessName      string
+	ProcessParameter string
+	CPUUsage         float32
+	Memory           uint64
+	SendClock        int64
+	SendBytes        int64
+	WriteClock       int64
+	WriteBytes       int64
+	ReadClock        int64
+	ReadBytes        int64
 }
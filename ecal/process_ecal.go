@@ -0,0 +1,111 @@
+//go:build !ecalfake
+
+package ecal
+
+import "C"
+import (
+	"errors"
+	"os"
+	"strings"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+)
+
+func ProcessSleepMS(sleepTimeMs int64) {
+	ecalc.ECAL_Process_SleepMS(sleepTimeMs)
+}
+
+// SetProcessState sets the health state shown for this process in the eCAL monitoring.
+func SetProcessState(severity int, level int, info string) {
+	ecalc.ECAL_Process_SetState(ecalc.ECAL_Process_eSeverity(severity), ecalc.ECAL_Process_eSeverity_Level(level), info)
+}
+
+// ProcessInfo returns the details eCAL keeps about this process.
+func ProcessInfo() (ProcessDetails, error) {
+	if ecalc.ECAL_IsInitialized(InitProcessReg) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitProcessReg)
+		if err != nil {
+			return ProcessDetails{}, err
+		}
+	}
+
+	return ProcessDetails{HostName: allocatedString(ecalc.ECAL_Process_GetHostName),
+		HostID:           ecalc.ECAL_Process_GetHostID(),
+		UnitName:         allocatedString(ecalc.ECAL_Process_GetUnitName),
+		ProcessID:        ecalc.ECAL_Process_GetProcessID(),
+		ProcessName:      allocatedString(ecalc.ECAL_Process_GetProcessName),
+		ProcessParameter: allocatedString(ecalc.ECAL_Process_GetProcessParameter),
+		CPUUsage:         ecalc.ECAL_Process_GetProcessCpuUsage(),
+		Memory:           ecalc.ECAL_Process_GetProcessMemory(),
+		SendClock:        ecalc.ECAL_Process_GetSClock(),
+		SendBytes:        ecalc.ECAL_Process_GetSBytes(),
+		WriteClock:       ecalc.ECAL_Process_GetWClock(),
+		WriteBytes:       ecalc.ECAL_Process_GetWBytes(),
+		ReadClock:        ecalc.ECAL_Process_GetRClock(),
+		ReadBytes:        ecalc.ECAL_Process_GetRBytes()}, nil
+}
+
+// allocatedString calls an eCAL getter with a buffer allocated by eCAL and returns its content as string.
+func allocatedString(get func(buffer uintptr, bufferLen int) int) string {
+	var cBuffer unsafe.Pointer
+	length := get(uintptr(unsafe.Pointer(&cBuffer)), ecalc.ECAL_ALLOCATE_4ME)
+	if cBuffer == nil {
+		return ""
+	}
+	defer ecalc.ECAL_FreeMem(uintptr(cBuffer))
+
+	if length <= 0 {
+		return ""
+	}
+	return C.GoStringN((*C.char)(cBuffer), C.int(length))
+}
+
+// StartProcess starts the executable with the arguments in workDir and returns its process ID. Arguments
+// containing spaces are quoted. Arguments containing quotes are rejected, as eCAL passes a single command line
+// and the platforms differ in how they unescape it.
+func StartProcess(path string, args []string, workDir string, opts StartOptions) (int, error) {
+	quotedArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.Contains(arg, "\"") {
+			return 0, errors.New("arguments must not contain quotes")
+		}
+		if strings.ContainsAny(arg, " \t") {
+			arg = "\"" + arg + "\""
+		}
+		quotedArgs = append(quotedArgs, arg)
+	}
+
+	createConsole := 0
+	if opts.CreateConsole {
+		createConsole = 1
+	}
+	block := 0
+	if opts.Block {
+		block = 1
+	}
+
+	processID := ecalc.ECAL_Process_StartProcess(path, strings.Join(quotedArgs, " "), workDir, createConsole,
+		ecalc.ECAL_Process_eStartMode(opts.Mode), block)
+	if processID == 0 {
+		return 0, errors.New("starting process failed")
+	}
+	return processID, nil
+}
+
+// StopProcessName stops all processes with the given name.
+func StopProcessName(processName string) error {
+	rc := ecalc.ECAL_Process_StopProcessName(processName)
+	if rc == 0 {
+		return errors.New("stopping process failed")
+	}
+	return nil
+}
+
+func StopProcessID(processID int) error {
+	rc := ecalc.ECAL_Process_StopProcessID(processID)
+	if rc == 0 {
+		return errors.New("stopping process failed")
+	}
+	return nil
+}
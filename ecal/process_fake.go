@@ -0,0 +1,110 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func ProcessSleepMS(sleepTimeMs int64) {
+	time.Sleep(time.Duration(sleepTimeMs) * time.Millisecond)
+}
+
+// ProcessInfo returns the details of this process. The write counters equal the send counters, CPU usage and
+// memory are not measured.
+func ProcessInfo() (ProcessDetails, error) {
+	hostName, _ := os.Hostname()
+	processName := ""
+	if len(os.Args) > 0 {
+		processName = os.Args[0]
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	return ProcessDetails{HostName: hostName,
+		HostID:           0,
+		UnitName:         registry.unitName,
+		ProcessID:        os.Getpid(),
+		ProcessName:      processName,
+		ProcessParameter: strings.Join(os.Args, " "),
+		CPUUsage:         0,
+		Memory:           0,
+		SendClock:        registry.sendClock,
+		SendBytes:        registry.sendBytes,
+		WriteClock:       registry.sendClock,
+		WriteBytes:       registry.sendBytes,
+		ReadClock:        registry.readClock,
+		ReadBytes:        registry.readBytes}, nil
+}
+
+// StartProcess simulates starting the executable: Nothing is run, but a process with a new ID shows up in the
+// monitoring data until it is stopped.
+func StartProcess(path string, args []string, workDir string, opts StartOptions) (int, error) {
+	for _, arg := range args {
+		if strings.Contains(arg, "\"") {
+			return 0, errors.New("arguments must not contain quotes")
+		}
+	}
+	if opts.Block {
+		return 0, errors.New("blocking starts not supported by the in-memory backend")
+	}
+
+	hostName, _ := os.Hostname()
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.processIDs++
+	processID := registry.processIDs
+	registry.processes[processID] = MonitoredProcess{HostName: hostName,
+		ProcessID:        processID,
+		ProcessName:      path,
+		UnitName:         filepath.Base(path),
+		ProcessParameter: strings.Join(args, " "),
+		Severity:         ProcessSeverityHealthy,
+		SeverityLevel:    ProcessSeverityLevel1}
+	return processID, nil
+}
+
+// StopProcessName stops all processes started with the given path.
+func StopProcessName(processName string) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	stopped := false
+	for processID, process := range registry.processes {
+		if process.ProcessName == processName {
+			delete(registry.processes, processID)
+			stopped = true
+		}
+	}
+	if !stopped {
+		return errors.New("stopping process failed")
+	}
+	return nil
+}
+
+func StopProcessID(processID int) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, ok := registry.processes[processID]; !ok {
+		return errors.New("stopping process failed")
+	}
+	delete(registry.processes, processID)
+	return nil
+}
+
+func SetProcessState(severity int, level int, info string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.severity = severity
+	registry.level = level
+	registry.stateInfo = info
+}
@@ -0,0 +1,71 @@
+package ecal
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ProtoTopicType returns the eCAL topic type of a protobuf message, e.g. "proto:pb.People.Person".
+func ProtoTopicType(message proto.Message) string {
+	return "proto:" + string(message.ProtoReflect().Descriptor().FullName())
+}
+
+// ProtoTopicDescription returns the eCAL topic description of a protobuf message: A serialized
+// FileDescriptorSet with the file defining the message and all files it depends on.
+func ProtoTopicDescription(message proto.Message) (string, error) {
+	fileSet := &descriptorpb.FileDescriptorSet{}
+	addProtoFile(fileSet, message.ProtoReflect().Descriptor().ParentFile(), make(map[string]bool))
+
+	description, err := proto.Marshal(fileSet)
+	if err != nil {
+		return "", err
+	}
+
+	return string(description), nil
+}
+
+// addProtoFile adds the file after all of its dependencies, so the set can be loaded front to back.
+func addProtoFile(fileSet *descriptorpb.FileDescriptorSet, file protoreflect.FileDescriptor, added map[string]bool) {
+	if added[file.Path()] {
+		return
+	}
+	added[file.Path()] = true
+
+	imports := file.Imports()
+	for idx := 0; idx < imports.Len(); idx++ {
+		addProtoFile(fileSet, imports.Get(idx).FileDescriptor, added)
+	}
+
+	fileSet.File = append(fileSet.File, protodesc.ToFileDescriptorProto(file))
+}
+
+// ProtoCodec encodes and decodes protobuf messages of type T, e.g. ProtoCodec[*pbexample.Person]. It also
+// provides the topic description, so the messages can be decoded by eCAL tools like the monitor.
+type ProtoCodec[T proto.Message] struct{}
+
+func (ProtoCodec[T]) Encode(value T) ([]byte, error) {
+	return proto.Marshal(value)
+}
+
+func (ProtoCodec[T]) Decode(content []byte) (T, error) {
+	var zero T
+	value := zero.ProtoReflect().Type().New().Interface().(T)
+	err := proto.Unmarshal(content, value)
+	return value, err
+}
+
+func (ProtoCodec[T]) TopicType() string {
+	var zero T
+	return ProtoTopicType(zero)
+}
+
+func (ProtoCodec[T]) TopicDescription() string {
+	var zero T
+	description, err := ProtoTopicDescription(zero)
+	if err != nil {
+		return ""
+	}
+	return description
+}
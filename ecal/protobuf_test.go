@@ -0,0 +1,106 @@
+package ecal
+
+import (
+	"testing"
+
+	"github.com/Blutkoete/golang-ecal/pbexample"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestProtoTopicType(t *testing.T) {
+	tests := []struct {
+		message   proto.Message
+		topicType string
+	}{
+		{&pbexample.Person{}, "proto:pb.People.Person"},
+		{&pbexample.Dog{}, "proto:pb.Animal.Dog"},
+		{&pbexample.House{}, "proto:pb.Environment.House"},
+		{(*pbexample.Person)(nil), "proto:pb.People.Person"},
+	}
+
+	for _, test := range tests {
+		if topicType := ProtoTopicType(test.message); topicType != test.topicType {
+			t.Errorf("ProtoTopicType(%T) = %q, want %q", test.message, topicType, test.topicType)
+		}
+	}
+}
+
+func TestProtoTopicDescription(t *testing.T) {
+	description, err := ProtoTopicDescription(&pbexample.Person{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileSet := &descriptorpb.FileDescriptorSet{}
+	err = proto.Unmarshal([]byte(description), fileSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every file follows the files it imports.
+	positions := make(map[string]int)
+	for idx, file := range fileSet.File {
+		if _, ok := positions[file.GetName()]; ok {
+			t.Errorf("file %s added twice", file.GetName())
+		}
+		positions[file.GetName()] = idx
+	}
+	for _, file := range fileSet.File {
+		for _, dependency := range file.GetDependency() {
+			position, ok := positions[dependency]
+			if !ok || position > positions[file.GetName()] {
+				t.Errorf("dependency %s of %s missing or added after it", dependency, file.GetName())
+			}
+		}
+	}
+	if last := fileSet.File[len(fileSet.File)-1].GetName(); last != "person.proto" {
+		t.Errorf("last file %s, want person.proto", last)
+	}
+
+	descriptor, err := ProtoMessageDescriptor(ProtoTopicType(&pbexample.Person{}), description)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if descriptor.Fields().ByName("house").Message().FullName() != "pb.Environment.House" {
+		t.Error("description does not resolve the imported House message")
+	}
+}
+
+func TestProtoCodec(t *testing.T) {
+	codec := ProtoCodec[*pbexample.Person]{}
+	person := &pbexample.Person{Id: 7,
+		Name:  "Max",
+		Stype: pbexample.Person_FEMALE,
+		Email: "max@example.com",
+		Dog:   &pbexample.Dog{Name: "Brandy"},
+		House: &pbexample.House{Rooms: 4}}
+
+	content, err := codec.Encode(person)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := codec.Decode(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(decoded, person) {
+		t.Errorf("decoded %v, want %v", decoded, person)
+	}
+
+	_, err = codec.Decode([]byte{0x0a, 0x05})
+	if err == nil {
+		t.Error("decoding truncated content succeeded")
+	}
+
+	if codec.TopicType() != "proto:pb.People.Person" {
+		t.Errorf("topic type %q", codec.TopicType())
+	}
+	description, err := ProtoTopicDescription(person)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codec.TopicDescription() != description {
+		t.Error("topic description differs from ProtoTopicDescription")
+	}
+}
@@ -1,59 +1,35 @@
+//go:build !ecalfake
+
 package ecal
 
 /*
 #include <stdlib.h>
+#include <ecal/ecalc.h>
 */
 import "C"
 import (
+	"context"
 	"errors"
-	"log"
 	"os"
 	"sync"
 	"unsafe"
 
 	"github.com/Blutkoete/golang-ecal/ecalc"
+	"github.com/mattn/go-pointer"
 )
 
-type PublisherIf interface {
-	Start() error
-	Stop() error
-	Destroy() error
-
-	IsStopped() bool
-	IsDestroyed() bool
-	IsSubscribed() bool
-
-	GetHandle() uintptr
-	GetInputChannel() chan<- Message
-	GetEventChannel() <-chan bool
-	GetTopic() string
-	GetType() string
-	GetDescription() string
-	GetQoS() (WriterQOS, error)
-	GetLayerMode() (int, int)
-	GetMaxBandwidthUDP() int64
-	GetID() int64
-
-	SetDescription(topicDesc string) error
-	SetQoS(qos WriterQOS) error
-	SetLayerMode(layerMode int, sendMode int) error
-	SetMaxBandwidthUDP(bandwidth int64) error
-	SetID(id int64) error
-
-	ShareType(state int) error
-	ShareDescription(state int) error
-
-	Dump() ([]byte, error)
-
-	send(message Message) error
-}
-
 type publisher struct {
 	handle          uintptr
 	running         bool
 	destroyed       bool
+	closed          bool
+	done            chan struct{}
+	closeSink       chan struct{}
+	workers         *sync.WaitGroup
 	inputSource     chan Message
-	eventSink       chan bool
+	eventSink       chan Event
+	errorSink       chan error
+	reference       unsafe.Pointer
 	topicName       string
 	topicType       string
 	topicDesc       string
@@ -61,6 +37,7 @@ type publisher struct {
 	sendMode        int
 	maxBandwidthUDP int64
 	id              int64
+	clock           Clock
 	mutex           *sync.Mutex
 }
 
@@ -71,22 +48,35 @@ func (pub *publisher) Start() error {
 	if pub.destroyed {
 		return errors.New("publisher already destroyed")
 	}
-	pub.running = true
 
-	go func() {
-		for !pub.destroyed && pub.running {
-			message := <-pub.inputSource
-
-			pub.mutex.Lock()
-			if Ok() && !pub.destroyed && pub.running {
-				pub.mutex.Unlock()
-				pub.send(message)
-			} else {
-				pub.mutex.Unlock()
-				break
+	if pub.running {
+		return nil
+	}
+	pub.running = true
+	pub.done = make(chan struct{})
+
+	pub.workers.Add(1)
+	go func(done chan struct{}) {
+		defer pub.workers.Done()
+
+		for {
+			select {
+			case message := <-pub.inputSource:
+				if !Ok() {
+					return
+				}
+				_, err := pub.send(message)
+				if err != nil {
+					select {
+					case pub.errorSink <- &SendError{Message: message, Err: err}:
+					default:
+					}
+				}
+			case <-done:
+				return
 			}
 		}
-	}()
+	}(pub.done)
 
 	return nil
 }
@@ -99,6 +89,9 @@ func (pub *publisher) Stop() error {
 		return errors.New("publisher already destroyed")
 	}
 
+	if pub.running {
+		close(pub.done)
+	}
 	pub.running = false
 	return nil
 }
@@ -111,15 +104,51 @@ func (pub *publisher) Destroy() error {
 	pub.mutex.Lock()
 	defer pub.mutex.Unlock()
 
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	for eventType := range pubEventTypes {
+		ecalc.ECAL_Pub_RemEventCallback(pub.handle, eventType)
+	}
+
 	rc := ecalc.ECAL_Pub_Destroy(pub.handle)
 	if rc == 0 {
 		return errors.New("could not destroy publisher")
 	}
 
+	pointer.Unref(pub.reference)
+	pub.reference = nil
+
 	pub.destroyed = true
 	return nil
 }
 
+// Close stops the publisher, waits for its worker to finish and destroys it. Messages not yet taken from
+// the input channel are discarded.
+func (pub *publisher) Close() error {
+	pub.mutex.Lock()
+	if pub.closed {
+		pub.mutex.Unlock()
+		return errors.New("publisher already closed")
+	}
+	pub.closed = true
+	pub.mutex.Unlock()
+
+	if !pub.IsDestroyed() {
+		pub.Stop()
+	}
+	pub.workers.Wait()
+
+	var err error
+	if !pub.IsDestroyed() {
+		err = pub.Destroy()
+	}
+
+	close(pub.closeSink)
+	return err
+}
+
 func (pub *publisher) IsStopped() bool {
 	pub.mutex.Lock()
 	defer pub.mutex.Unlock()
@@ -153,11 +182,16 @@ func (pub *publisher) GetInputChannel() chan<- Message {
 	return pub.inputSource
 }
 
-func (pub *publisher) GetEventChannel() <-chan bool {
-	log.Println("events not supported")
+func (pub *publisher) GetEventChannel() <-chan Event {
 	return pub.eventSink
 }
 
+// GetErrorChannel returns a channel reporting messages from the input channel that could not be sent as
+// *SendError. Errors are dropped while the channel is full.
+func (pub *publisher) GetErrorChannel() <-chan error {
+	return pub.errorSink
+}
+
 func (pub *publisher) GetTopic() string {
 	return pub.topicName
 }
@@ -199,6 +233,13 @@ func (pub *publisher) GetID() int64 {
 	return pub.id
 }
 
+func (pub *publisher) GetClock() Clock {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	return pub.clock
+}
+
 func (pub *publisher) SetDescription(topicDesc string) error {
 	pub.mutex.Lock()
 	defer pub.mutex.Unlock()
@@ -284,6 +325,13 @@ func (pub *publisher) SetID(id int64) error {
 	return nil
 }
 
+func (pub *publisher) SetClock(clock Clock) {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	pub.clock = clock
+}
+
 func (pub *publisher) ShareType(state int) error {
 	pub.mutex.Lock()
 	defer pub.mutex.Unlock()
@@ -329,29 +377,63 @@ func (pub *publisher) Dump() ([]byte, error) {
 	return dump, nil
 }
 
-func (pub *publisher) send(message Message) error {
+func (pub *publisher) Send(ctx context.Context, message Message) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return pub.send(message)
+}
+
+func (pub *publisher) send(message Message) (int, error) {
 	pub.mutex.Lock()
 	defer pub.mutex.Unlock()
 
 	if pub.destroyed {
-		return errors.New("publisher already destroyed")
+		return 0, errors.New("publisher already destroyed")
 	}
 
-	if !pub.running {
-		return errors.New("publisher stopped")
+	if pub.closed {
+		return 0, errors.New("publisher already closed")
 	}
 
 	if message.Content == nil || len(message.Content) == 0 {
-		return errors.New("no data to send")
+		return 0, errors.New("no data to send")
+	}
+
+	if message.Timestamp == -1 && pub.clock != nil {
+		message.Timestamp = TimestampFromTime(pub.clock.Now())
 	}
 
 	bytesSent := ecalc.ECAL_Pub_Send(pub.handle, uintptr(unsafe.Pointer(&message.Content[0])), len(message.Content), message.Timestamp)
 	if bytesSent < len(message.Content) {
-		log.Println("error sending", bytesSent, len(message.Content))
-		return errors.New("error sending")
+		return bytesSent, errors.New("error sending")
 	}
 
-	return nil
+	return bytesSent, nil
+}
+
+var pubEventTypes = map[ecalc.Enum_SS_eCAL_Publisher_Event]int{
+	ecalc.Pub_event_connected:    EventConnected,
+	ecalc.Pub_event_disconnected: EventDisconnected,
+	ecalc.Pub_event_dropped:      EventDropped,
+}
+
+//export goPubEventCallback
+func goPubEventCallback(cTopicName *C.char, cData *C.struct_SPubEventCallbackDataC, par unsafe.Pointer) {
+	pub, ok := pointer.Restore(par).(*publisher)
+	if !ok || cData == nil {
+		return
+	}
+
+	eventType, ok := pubEventTypes[ecalc.Enum_SS_eCAL_Publisher_Event(cData._type)]
+	if !ok {
+		eventType = EventNone
+	}
+
+	deliverEvent(pub.eventSink, Event{Type: eventType,
+		Time:  int64(cData.time),
+		Clock: int64(cData.clock)})
 }
 
 func PublisherCreate(topicName string, topicType string, topicDesc string, start bool) (PublisherIf, chan<- Message, error) {
@@ -376,8 +458,14 @@ func PublisherCreate(topicName string, topicType string, topicDesc string, start
 	pub := publisher{handle: handle,
 		running:         false,
 		destroyed:       false,
+		closed:          false,
+		done:            make(chan struct{}),
+		closeSink:       make(chan struct{}),
+		workers:         &sync.WaitGroup{},
 		inputSource:     make(chan Message),
-		eventSink:       make(chan bool),
+		eventSink:       make(chan Event, eventBufferSize),
+		errorSink:       make(chan error, errorBufferSize),
+		reference:       nil,
 		topicName:       topicName,
 		topicType:       topicType,
 		topicDesc:       topicDesc,
@@ -385,7 +473,18 @@ func PublisherCreate(topicName string, topicType string, topicDesc string, start
 		sendMode:        SModeAuto,
 		maxBandwidthUDP: -1,
 		id:              -1,
+		clock:           nil,
 		mutex:           &sync.Mutex{}}
+	pub.reference = pointer.Save(&pub)
+
+	for eventType := range pubEventTypes {
+		rc = ecalc.ECAL_Pub_AddEventCallbackC(handle, eventType, pubEventCallbackPtr(), uintptr(pub.reference))
+		if rc == 0 {
+			pub.Destroy()
+			return nil, nil, errors.New("adding event callback failed")
+		}
+	}
+
 	if start {
 		err = pub.Start()
 		if err != nil {
@@ -395,3 +494,14 @@ func PublisherCreate(topicName string, topicType string, topicDesc string, start
 
 	return &pub, pub.GetInputChannel(), nil
 }
+
+// PublisherCreateContext works like PublisherCreate, but the publisher is closed as soon as the context is done.
+func PublisherCreateContext(ctx context.Context, topicName string, topicType string, topicDesc string, start bool) (PublisherIf, chan<- Message, error) {
+	pub, pubChannel, err := PublisherCreate(topicName, topicType, topicDesc, start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go closeOnDone(ctx, pub.(*publisher).closeSink, pub.Close)
+	return pub, pubChannel, nil
+}
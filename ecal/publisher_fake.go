@@ -0,0 +1,397 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type memoryPublisher struct {
+	handle          uintptr
+	running         bool
+	destroyed       bool
+	closed          bool
+	done            chan struct{}
+	closeSink       chan struct{}
+	workers         *sync.WaitGroup
+	inputSource     chan Message
+	eventSink       chan Event
+	errorSink       chan error
+	topicName       string
+	topicType       string
+	topicDesc       string
+	qos             WriterQOS
+	layerMode       int
+	sendMode        int
+	maxBandwidthUDP int64
+	id              int64
+	dataClock       int64
+	clock           Clock
+	mutex           *sync.Mutex
+}
+
+func (pub *memoryPublisher) Start() error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	if pub.running {
+		return nil
+	}
+	pub.running = true
+	pub.done = make(chan struct{})
+
+	pub.workers.Add(1)
+	go func(done chan struct{}) {
+		defer pub.workers.Done()
+
+		for {
+			select {
+			case message := <-pub.inputSource:
+				if !Ok() {
+					return
+				}
+				_, err := pub.send(message)
+				if err != nil {
+					select {
+					case pub.errorSink <- &SendError{Message: message, Err: err}:
+					default:
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}(pub.done)
+
+	return nil
+}
+
+func (pub *memoryPublisher) Stop() error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	if pub.running {
+		close(pub.done)
+	}
+	pub.running = false
+	return nil
+}
+
+func (pub *memoryPublisher) Destroy() error {
+	if pub.running {
+		pub.Stop()
+	}
+
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	registry.removePublisher(pub)
+	pub.destroyed = true
+	return nil
+}
+
+func (pub *memoryPublisher) Close() error {
+	pub.mutex.Lock()
+	if pub.closed {
+		pub.mutex.Unlock()
+		return errors.New("publisher already closed")
+	}
+	pub.closed = true
+	pub.mutex.Unlock()
+
+	if !pub.IsDestroyed() {
+		pub.Stop()
+	}
+	pub.workers.Wait()
+
+	var err error
+	if !pub.IsDestroyed() {
+		err = pub.Destroy()
+	}
+
+	close(pub.closeSink)
+	return err
+}
+
+func (pub *memoryPublisher) IsStopped() bool {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	return !pub.running
+}
+
+func (pub *memoryPublisher) IsDestroyed() bool {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	return pub.destroyed
+}
+
+func (pub *memoryPublisher) IsSubscribed() bool {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return false
+	}
+
+	return registry.isSubscribed(pub)
+}
+
+func (pub *memoryPublisher) GetHandle() uintptr {
+	return pub.handle
+}
+
+func (pub *memoryPublisher) GetInputChannel() chan<- Message {
+	return pub.inputSource
+}
+
+func (pub *memoryPublisher) GetEventChannel() <-chan Event {
+	return pub.eventSink
+}
+
+func (pub *memoryPublisher) GetErrorChannel() <-chan error {
+	return pub.errorSink
+}
+
+func (pub *memoryPublisher) GetTopic() string {
+	return pub.topicName
+}
+
+func (pub *memoryPublisher) GetType() string {
+	return pub.topicType
+}
+
+func (pub *memoryPublisher) GetDescription() string {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	return pub.topicDesc
+}
+
+func (pub *memoryPublisher) GetQoS() (WriterQOS, error) {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return WriterQOS{KeepLastHistoryQOS, BestEffortReliability}, errors.New("publisher already destroyed")
+	}
+
+	return pub.qos, nil
+}
+
+func (pub *memoryPublisher) GetLayerMode() (int, int) {
+	return pub.layerMode, pub.sendMode
+}
+
+func (pub *memoryPublisher) GetMaxBandwidthUDP() int64 {
+	return pub.maxBandwidthUDP
+}
+
+func (pub *memoryPublisher) GetID() int64 {
+	return pub.id
+}
+
+func (pub *memoryPublisher) GetClock() Clock {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	return pub.clock
+}
+
+func (pub *memoryPublisher) SetDescription(topicDesc string) error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	pub.topicDesc = topicDesc
+	return nil
+}
+
+func (pub *memoryPublisher) SetQoS(qos WriterQOS) error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	pub.qos = qos
+	return nil
+}
+
+func (pub *memoryPublisher) SetLayerMode(layerMode int, sendMode int) error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	pub.layerMode = layerMode
+	pub.sendMode = sendMode
+	return nil
+}
+
+func (pub *memoryPublisher) SetMaxBandwidthUDP(bandwidth int64) error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	pub.maxBandwidthUDP = bandwidth
+	return nil
+}
+
+func (pub *memoryPublisher) SetID(id int64) error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	pub.id = id
+	return nil
+}
+
+func (pub *memoryPublisher) SetClock(clock Clock) {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	pub.clock = clock
+}
+
+func (pub *memoryPublisher) ShareType(state int) error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	return errors.New("not implemented")
+}
+
+func (pub *memoryPublisher) ShareDescription(state int) error {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return errors.New("publisher already destroyed")
+	}
+
+	return errors.New("not implemented")
+}
+
+func (pub *memoryPublisher) Dump() ([]byte, error) {
+	return nil, errors.New("dump not supported by the in-memory backend")
+}
+
+func (pub *memoryPublisher) Send(ctx context.Context, message Message) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return pub.send(message)
+}
+
+func (pub *memoryPublisher) send(message Message) (int, error) {
+	pub.mutex.Lock()
+	defer pub.mutex.Unlock()
+
+	if pub.destroyed {
+		return 0, errors.New("publisher already destroyed")
+	}
+
+	if pub.closed {
+		return 0, errors.New("publisher already closed")
+	}
+
+	if message.Content == nil || len(message.Content) == 0 {
+		return 0, errors.New("no data to send")
+	}
+
+	pub.dataClock++
+	message.ID = pub.id
+	message.Clock = pub.dataClock
+	if message.Timestamp == -1 && pub.clock != nil {
+		message.Timestamp = TimestampFromTime(pub.clock.Now())
+	} else if message.Timestamp == -1 {
+		message.Timestamp = memoryTime()
+	}
+
+	registry.publish(pub, message)
+	return len(message.Content), nil
+}
+
+func PublisherCreate(topicName string, topicType string, topicDesc string, start bool) (PublisherIf, chan<- Message, error) {
+	var err error
+	if !registry.isInitialized(InitPublisher) {
+		err = Initialize(nil, "", InitPublisher)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pub := &memoryPublisher{handle: 0,
+		running:         false,
+		destroyed:       false,
+		closed:          false,
+		done:            make(chan struct{}),
+		closeSink:       make(chan struct{}),
+		workers:         &sync.WaitGroup{},
+		inputSource:     make(chan Message),
+		eventSink:       make(chan Event, eventBufferSize),
+		errorSink:       make(chan error, errorBufferSize),
+		topicName:       topicName,
+		topicType:       topicType,
+		topicDesc:       topicDesc,
+		qos:             WriterQOS{KeepLastHistoryQOS, BestEffortReliability},
+		layerMode:       TLayerInProc,
+		sendMode:        SModeAuto,
+		maxBandwidthUDP: -1,
+		id:              -1,
+		dataClock:       0,
+		clock:           nil,
+		mutex:           &sync.Mutex{}}
+	registry.addPublisher(pub)
+
+	if start {
+		err = pub.Start()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return pub, pub.GetInputChannel(), nil
+}
+
+func PublisherCreateContext(ctx context.Context, topicName string, topicType string, topicDesc string, start bool) (PublisherIf, chan<- Message, error) {
+	pub, pubChannel, err := PublisherCreate(topicName, topicType, topicDesc, start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go closeOnDone(ctx, pub.(*memoryPublisher).closeSink, pub.Close)
+	return pub, pubChannel, nil
+}
@@ -0,0 +1,173 @@
+//go:build ecalfake
+
+package ecal
+
+// The in-memory backend replaces eCAL when building with the ecalfake tag, e.g. "go test -tags ecalfake ./...".
+// Publishers and subscribers are matched by topic name and type within the process, so code using them can be
+// tested without eCAL installed. Service servers and clients are not available with this backend. Processes
+// started via StartProcess are simulated: They only appear in the monitoring data until they are stopped.
+
+import (
+	"regexp"
+	"sync"
+	"time"
+)
+
+// memoryProcessIDBase is the first ID of a simulated process, above the IDs used by Linux by default.
+const memoryProcessIDBase = 1 << 22
+
+// memoryHistoryDepth is the number of messages kept for a subscriber unless both sides use KeepAllHistoryQOS.
+// Older messages are dropped and reported as EventDropped.
+const memoryHistoryDepth = 8
+
+type memoryRegistry struct {
+	initialized uint
+	shutdown    bool
+	unitName    string
+	include     *regexp.Regexp
+	exclude     *regexp.Regexp
+	logLevel    int
+	logs        []LogMessage
+	severity    int
+	level       int
+	stateInfo   string
+	sendClock   int64
+	sendBytes   int64
+	readClock   int64
+	readBytes   int64
+	handles     uintptr
+	processIDs  int
+	processes   map[int]MonitoredProcess
+	events      map[string]chan struct{}
+	openEvents  map[uintptr]chan struct{}
+	publishers  map[*memoryPublisher]struct{}
+	subscribers map[*memorySubscriber]struct{}
+	mutex       *sync.Mutex
+}
+
+var registry = &memoryRegistry{initialized: 0,
+	shutdown:    false,
+	unitName:    "",
+	include:     nil,
+	exclude:     nil,
+	logLevel:    LogLevelInfo,
+	logs:        make([]LogMessage, 0),
+	severity:    ProcessSeverityUnknown,
+	level:       ProcessSeverityLevel1,
+	stateInfo:   "",
+	sendClock:   0,
+	sendBytes:   0,
+	readClock:   0,
+	readBytes:   0,
+	handles:     0,
+	processIDs:  memoryProcessIDBase,
+	processes:   make(map[int]MonitoredProcess),
+	events:      make(map[string]chan struct{}),
+	openEvents:  make(map[uintptr]chan struct{}),
+	publishers:  make(map[*memoryPublisher]struct{}),
+	subscribers: make(map[*memorySubscriber]struct{}),
+	mutex:       &sync.Mutex{}}
+
+func (reg *memoryRegistry) isInitialized(components uint) bool {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	return reg.initialized&components == components
+}
+
+func (reg *memoryRegistry) addPublisher(pub *memoryPublisher) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	reg.handles++
+	pub.handle = reg.handles
+	reg.publishers[pub] = struct{}{}
+	for sub := range reg.subscribers {
+		if topicsMatch(pub.topicName, pub.topicType, sub.topicName, sub.topicType) {
+			connect(pub, sub, EventConnected)
+		}
+	}
+}
+
+func (reg *memoryRegistry) removePublisher(pub *memoryPublisher) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	delete(reg.publishers, pub)
+	for sub := range reg.subscribers {
+		if topicsMatch(pub.topicName, pub.topicType, sub.topicName, sub.topicType) {
+			connect(pub, sub, EventDisconnected)
+		}
+	}
+}
+
+func (reg *memoryRegistry) addSubscriber(sub *memorySubscriber) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	reg.handles++
+	sub.handle = reg.handles
+	reg.subscribers[sub] = struct{}{}
+	for pub := range reg.publishers {
+		if topicsMatch(pub.topicName, pub.topicType, sub.topicName, sub.topicType) {
+			connect(pub, sub, EventConnected)
+		}
+	}
+}
+
+func (reg *memoryRegistry) removeSubscriber(sub *memorySubscriber) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	delete(reg.subscribers, sub)
+	for pub := range reg.publishers {
+		if topicsMatch(pub.topicName, pub.topicType, sub.topicName, sub.topicType) {
+			connect(pub, sub, EventDisconnected)
+		}
+	}
+}
+
+func (reg *memoryRegistry) isSubscribed(pub *memoryPublisher) bool {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	for sub := range reg.subscribers {
+		if topicsMatch(pub.topicName, pub.topicType, sub.topicName, sub.topicType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (reg *memoryRegistry) publish(pub *memoryPublisher, message Message) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	reg.sendClock++
+	reg.sendBytes += int64(len(message.Content))
+	for sub := range reg.subscribers {
+		if topicsMatch(pub.topicName, pub.topicType, sub.topicName, sub.topicType) {
+			sub.enqueue(message, pub.qos)
+			reg.readClock++
+			reg.readBytes += int64(len(message.Content))
+		}
+	}
+}
+
+// topicsMatch reports whether a publisher and a subscriber are connected. An empty type matches any type.
+func topicsMatch(pubName string, pubType string, subName string, subType string) bool {
+	return pubName == subName && (pubType == "" || subType == "" || pubType == subType)
+}
+
+func connect(pub *memoryPublisher, sub *memorySubscriber, eventType int) {
+	event := Event{Type: eventType,
+		Time:  memoryTime(),
+		Clock: 0}
+	deliverEvent(pub.eventSink, event)
+	deliverEvent(sub.eventSink, event)
+}
+
+// memoryTime returns the current time in microseconds like the eCAL timestamps.
+func memoryTime() int64 {
+	return time.Now().UnixNano() / 1000
+}
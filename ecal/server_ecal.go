@@ -0,0 +1,229 @@
+//go:build !ecalfake
+
+package ecal
+
+/*
+#include <stdlib.h>
+#include <ecal/ecalc.h>
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+	"github.com/mattn/go-pointer"
+)
+
+// MethodCallback handles a single service call. The returned bytes are sent back as response. If an error
+// is returned, its message is sent as response instead and the call reports a return state of -1.
+type MethodCallback func(ctx context.Context, method string, request []byte) ([]byte, error)
+
+type ServerIf interface {
+	Destroy() error
+
+	IsDestroyed() bool
+
+	GetHandle() uintptr
+	GetServiceName() string
+	GetMethods() []string
+
+	AddMethodCallback(method string, requestType string, responseType string, callback MethodCallback) error
+	RemMethodCallback(method string) error
+}
+
+type serverMethod struct {
+	server       *server
+	name         string
+	requestType  string
+	responseType string
+	callback     MethodCallback
+	reference    unsafe.Pointer
+	mutex        *sync.Mutex
+}
+
+type server struct {
+	handle      uintptr
+	destroyed   bool
+	serviceName string
+	methods     map[string]*serverMethod
+	ctx         context.Context
+	cancel      context.CancelFunc
+	mutex       *sync.Mutex
+}
+
+func (srv *server) Destroy() error {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	if srv.destroyed {
+		return errors.New("server already destroyed")
+	}
+
+	for name, method := range srv.methods {
+		ecalc.ECAL_Server_RemMethodCallbackC(srv.handle, name)
+		method.release()
+	}
+	srv.methods = make(map[string]*serverMethod)
+	srv.cancel()
+
+	rc := ecalc.ECAL_Server_Destroy(srv.handle)
+	if rc == 0 {
+		return errors.New("could not destroy server")
+	}
+
+	srv.destroyed = true
+	return nil
+}
+
+func (srv *server) IsDestroyed() bool {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	return srv.destroyed
+}
+
+func (srv *server) GetHandle() uintptr {
+	return srv.handle
+}
+
+func (srv *server) GetServiceName() string {
+	return srv.serviceName
+}
+
+func (srv *server) GetMethods() []string {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	methods := make([]string, 0, len(srv.methods))
+	for name := range srv.methods {
+		methods = append(methods, name)
+	}
+	return methods
+}
+
+func (srv *server) AddMethodCallback(method string, requestType string, responseType string, callback MethodCallback) error {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	if srv.destroyed {
+		return errors.New("server already destroyed")
+	}
+
+	if callback == nil {
+		return errors.New("no callback given")
+	}
+
+	if _, exists := srv.methods[method]; exists {
+		return errors.New("method already registered")
+	}
+
+	serverMethod := &serverMethod{server: srv,
+		name:         method,
+		requestType:  requestType,
+		responseType: responseType,
+		callback:     callback,
+		mutex:        &sync.Mutex{}}
+	serverMethod.reference = pointer.Save(serverMethod)
+
+	rc := ecalc.ECAL_Server_AddMethodCallbackC(srv.handle, method, requestType, responseType, serverMethodCallbackPtr(), uintptr(serverMethod.reference))
+	if rc == 0 {
+		serverMethod.release()
+		return errors.New("adding method callback failed")
+	}
+
+	srv.methods[method] = serverMethod
+	return nil
+}
+
+func (srv *server) RemMethodCallback(method string) error {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	if srv.destroyed {
+		return errors.New("server already destroyed")
+	}
+
+	serverMethod, exists := srv.methods[method]
+	if !exists {
+		return errors.New("method not registered")
+	}
+
+	rc := ecalc.ECAL_Server_RemMethodCallbackC(srv.handle, method)
+	if rc == 0 {
+		return errors.New("removing method callback failed")
+	}
+
+	serverMethod.release()
+	delete(srv.methods, method)
+	return nil
+}
+
+// release drops the reference handed to eCAL once the method is no longer registered.
+func (method *serverMethod) release() {
+	method.mutex.Lock()
+	defer method.mutex.Unlock()
+
+	pointer.Unref(method.reference)
+	method.reference = nil
+}
+
+func (method *serverMethod) call(request []byte, cResponse *unsafe.Pointer, cResponseLen *C.int) C.int {
+	response, err := method.callback(method.server.ctx, method.name, request)
+	retState := C.int(0)
+	if err != nil {
+		response = []byte(err.Error())
+		retState = -1
+	}
+
+	// The response is freed by the C trampoline once eCAL copied it, see callback_ecal.go.
+	if len(response) > 0 {
+		*cResponse = C.CBytes(response)
+	}
+	*cResponseLen = C.int(len(response))
+
+	return retState
+}
+
+//export goServerMethodCallback
+func goServerMethodCallback(cMethod *C.char, cRequestType *C.char, cResponseType *C.char, cRequest *C.char, cRequestLen C.int, cResponse *unsafe.Pointer, cResponseLen *C.int, par unsafe.Pointer) C.int {
+	method, ok := pointer.Restore(par).(*serverMethod)
+	if !ok {
+		return -1
+	}
+
+	var request []byte
+	if cRequestLen > 0 {
+		request = C.GoBytes(unsafe.Pointer(cRequest), cRequestLen)
+	}
+
+	return method.call(request, cResponse, cResponseLen)
+}
+
+func ServerCreate(serviceName string) (ServerIf, error) {
+	if ecalc.ECAL_IsInitialized(InitService) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitService)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	handle := ecalc.ECAL_Server_Create(serviceName)
+	if handle == 0 {
+		return nil, errors.New("could not create new server")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := server{handle: handle,
+		destroyed:   false,
+		serviceName: serviceName,
+		methods:     make(map[string]*serverMethod),
+		ctx:         ctx,
+		cancel:      cancel,
+		mutex:       &sync.Mutex{}}
+
+	return &srv, nil
+}
@@ -0,0 +1,51 @@
+package ecal
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// shutdownPollInterval is how often Ok is checked to detect a shutdown request, as eCAL offers no notification.
+const shutdownPollInterval = 100 * time.Millisecond
+
+var shutdownSink = make(chan struct{})
+var shutdownOnce = &sync.Once{}
+
+// ShutdownChannel returns a channel that is closed once this process was asked to shut down, e.g. by
+// ShutdownUnitName from another process, or was finalized. eCAL is initialized if it is not running yet; the
+// channel is closed right away if it cannot be started.
+func ShutdownChannel() <-chan struct{} {
+	shutdownOnce.Do(func() {
+		if !Ok() {
+			Initialize(os.Args, os.Args[0], InitProcessReg)
+		}
+		go watchShutdown()
+	})
+	return shutdownSink
+}
+
+// ShutdownContext returns a context that is cancelled once this process was asked to shut down.
+func ShutdownContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	shutdown := ShutdownChannel()
+	go func() {
+		select {
+		case <-shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+func watchShutdown() {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+
+	for Ok() {
+		<-ticker.C
+	}
+	close(shutdownSink)
+}
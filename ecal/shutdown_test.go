@@ -0,0 +1,45 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownChannelBeforeInitialize(t *testing.T) {
+	// Start like a fresh process, where eCAL is only initialized by the first publisher or subscriber.
+	shutdownSink = make(chan struct{})
+	shutdownOnce = &sync.Once{}
+	registry.mutex.Lock()
+	initialized := registry.initialized
+	registry.initialized = 0
+	registry.mutex.Unlock()
+	defer func() {
+		registry.mutex.Lock()
+		registry.initialized |= initialized
+		registry.shutdown = false
+		registry.mutex.Unlock()
+	}()
+
+	shutdown := ShutdownChannel()
+	select {
+	case <-shutdown:
+		t.Fatal("shutdown channel closed before a shutdown was requested")
+	case <-time.After(3 * shutdownPollInterval):
+	}
+
+	pub, _, err := PublisherCreate("shutdown_topic", "", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pub.Close()
+
+	ShutdownProcesses()
+	select {
+	case <-shutdown:
+	case <-time.After(testTimeout):
+		t.Fatal("shutdown channel not closed after a shutdown was requested")
+	}
+}
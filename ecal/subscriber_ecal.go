@@ -0,0 +1,631 @@
+//go:build !ecalfake
+
+package ecal
+
+/*
+#include <stdlib.h>
+#include <ecal/ecalc.h>
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+	"github.com/mattn/go-pointer"
+)
+
+type subscriber struct {
+	handle      uintptr
+	bufferSize  int
+	rejected    int64
+	received    int64
+	running     bool
+	destroyed   bool
+	closed      bool
+	receiveMode int
+	handler     func(Message)
+	reference   unsafe.Pointer
+	done        chan struct{}
+	closeSink   chan struct{}
+	workers     *sync.WaitGroup
+	outputSink  chan Message
+	eventSink   chan Event
+	topicName   string
+	topicType   string
+	topicDesc   string
+	ids         []int64
+	timeout     int
+	mutex       *sync.Mutex
+}
+
+func (sub *subscriber) Start() error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	if sub.running {
+		return nil
+	}
+
+	sub.done = make(chan struct{})
+
+	if sub.receiveMode == ReceiveModeCallback {
+		rc := ecalc.ECAL_Sub_AddReceiveCallbackC(sub.handle, subReceiveCallbackPtr(), uintptr(sub.reference))
+		if rc == 0 {
+			return errors.New("adding receive callback failed")
+		}
+
+		sub.running = true
+		return nil
+	}
+	sub.running = true
+
+	sub.workers.Add(1)
+	if sub.receiveMode == ReceiveModeAlloc {
+		go sub.receiveAlloc(sub.done)
+		return nil
+	}
+
+	go func(sub *subscriber, done chan struct{}) {
+		defer sub.workers.Done()
+
+		cBuffer := C.malloc(C.ulong(sub.bufferSize))
+		defer C.free(cBuffer)
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			message := Message{Content: nil,
+				Timestamp: 0}
+			bytesReceived := ecalc.ECAL_Sub_Receive(sub.handle, uintptr(cBuffer), sub.bufferSize, &message.Timestamp, 100)
+			if bytesReceived <= 0 {
+				continue
+			} else if bytesReceived > sub.bufferSize {
+				log.Println("received more data than pre-allocated")
+				atomic.AddInt64(&sub.rejected, 1)
+				continue
+			}
+
+			atomic.StoreInt64(&sub.received, time.Now().UnixNano())
+			message.Content = make([]byte, bytesReceived, bytesReceived)
+			gBuffer := (*[1 << 30]byte)(cBuffer)
+			copy(message.Content, gBuffer[:bytesReceived])
+
+			select {
+			case sub.outputSink <- message:
+			case <-done:
+				return
+			}
+		}
+
+	}(sub, sub.done)
+	return nil
+}
+
+// receiveAlloc polls for messages with buffers allocated by eCAL to fit each message. Messages larger
+// than a non-zero bufferSize are rejected.
+func (sub *subscriber) receiveAlloc(done chan struct{}) {
+	defer sub.workers.Done()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		message := Message{Content: nil,
+			Timestamp: 0}
+		var cBuffer unsafe.Pointer
+		bytesReceived := ecalc.ECAL_Sub_Receive_Alloc(sub.handle, (*uintptr)(unsafe.Pointer(&cBuffer)), &message.Timestamp, 100)
+		if cBuffer == nil {
+			continue
+		}
+
+		if bytesReceived <= 0 {
+			ecalc.ECAL_FreeMem(uintptr(cBuffer))
+			continue
+		} else if sub.bufferSize > 0 && bytesReceived > sub.bufferSize {
+			ecalc.ECAL_FreeMem(uintptr(cBuffer))
+			atomic.AddInt64(&sub.rejected, 1)
+			continue
+		}
+
+		atomic.StoreInt64(&sub.received, time.Now().UnixNano())
+		message.Content = C.GoBytes(cBuffer, C.int(bytesReceived))
+		ecalc.ECAL_FreeMem(uintptr(cBuffer))
+
+		select {
+		case sub.outputSink <- message:
+		case <-done:
+			return
+		}
+	}
+}
+
+func (sub *subscriber) Stop() error {
+	sub.mutex.Lock()
+
+	if sub.destroyed {
+		sub.mutex.Unlock()
+		return errors.New("subscriber already destroyed")
+	}
+
+	if !sub.running {
+		sub.mutex.Unlock()
+		return nil
+	}
+
+	// Closing done releases the workers blocked on the output channel. The receive callback is removed
+	// without holding the mutex, as eCAL waits for a running callback to return.
+	close(sub.done)
+	sub.running = false
+	sub.mutex.Unlock()
+
+	if sub.receiveMode == ReceiveModeCallback {
+		rc := ecalc.ECAL_Sub_RemReceiveCallback(sub.handle)
+		if rc == 0 {
+			return errors.New("removing receive callback failed")
+		}
+	}
+
+	return nil
+}
+
+func (sub *subscriber) Destroy() error {
+	if sub.running {
+		sub.Stop()
+	}
+
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	for eventType := range subEventTypes {
+		ecalc.ECAL_Sub_RemEventCallback(sub.handle, eventType)
+	}
+
+	rc := ecalc.ECAL_Sub_Destroy(sub.handle)
+	if rc == 0 {
+		return errors.New("could not destroy subscriber")
+	}
+
+	if sub.reference != nil {
+		pointer.Unref(sub.reference)
+		sub.reference = nil
+	}
+
+	sub.destroyed = true
+	return nil
+}
+
+// Close stops the subscriber, waits for its worker and pending deliveries to finish, destroys it and closes
+// the output channel. It must not be called from a message handler.
+func (sub *subscriber) Close() error {
+	sub.mutex.Lock()
+	if sub.closed {
+		sub.mutex.Unlock()
+		return errors.New("subscriber already closed")
+	}
+	sub.closed = true
+	sub.mutex.Unlock()
+
+	if !sub.IsDestroyed() {
+		sub.Stop()
+	}
+	sub.workers.Wait()
+
+	var err error
+	if !sub.IsDestroyed() {
+		err = sub.Destroy()
+	}
+
+	close(sub.outputSink)
+	close(sub.closeSink)
+	return err
+}
+
+func (sub *subscriber) IsStopped() bool {
+	return !sub.running
+}
+
+func (sub *subscriber) IsDestroyed() bool {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	return sub.destroyed
+}
+
+func (sub *subscriber) GetHandle() uintptr {
+	return sub.handle
+}
+
+func (sub *subscriber) GetBufferSize() int {
+	return sub.bufferSize
+}
+
+func (sub *subscriber) GetRejectedCount() int64 {
+	return atomic.LoadInt64(&sub.rejected)
+}
+
+func (sub *subscriber) GetLastReceiveTime() time.Time {
+	received := atomic.LoadInt64(&sub.received)
+	if received == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, received)
+}
+
+func (sub *subscriber) GetOutputChannel() <-chan Message {
+	return sub.outputSink
+}
+
+func (sub *subscriber) GetEventChannel() <-chan Event {
+	return sub.eventSink
+}
+
+func (sub *subscriber) GetTopic() string {
+	return sub.topicName
+}
+
+func (sub *subscriber) GetType() string {
+	return sub.topicType
+}
+
+func (sub *subscriber) GetDescription() string {
+	return sub.topicDesc
+}
+
+func (sub *subscriber) GetQoS() (ReaderQOS, error) {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return ReaderQOS{BestEffortReliability, KeepLastHistoryQOS}, errors.New("subscriber already destroyed")
+	}
+
+	var cQOS ecalc.SReaderQOSC
+	rc := ecalc.ECAL_Sub_GetQOS(sub.handle, cQOS)
+	if rc == 0 {
+		return ReaderQOS{BestEffortReliability, KeepLastHistoryQOS}, errors.New("getting QOS failed")
+	}
+
+	return ReaderQOS{(int)(cQOS.GetReliability()), (int)(cQOS.GetHistory_kind())}, nil
+}
+
+func (sub *subscriber) GetIDs() []int64 {
+	return sub.ids
+}
+
+func (sub *subscriber) GetTimeout() int {
+	return sub.timeout
+}
+
+func (sub *subscriber) GetReceiveMode() int {
+	return sub.receiveMode
+}
+
+func (sub *subscriber) SetQoS(qos ReaderQOS) error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	var cQOS ecalc.SReaderQOSC
+	cQOS.SetReliability((ecalc.Enum_SS_eQOSPolicy_ReliabilityC)(qos.Reliability))
+	cQOS.SetHistory_kind((ecalc.Enum_SS_eQOSPolicy_HistoryKindC)(qos.HistoryKind))
+	rc := ecalc.ECAL_Sub_SetQOS(sub.handle, cQOS)
+	if rc == 0 {
+		return errors.New("setting QOS failed")
+	}
+
+	return nil
+}
+
+func (sub *subscriber) SetIDs(ids []int64) error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	rc := ecalc.ECAL_Sub_SetID(sub.handle, (*int64)(unsafe.Pointer(&ids[0])), len(ids))
+	if rc == 0 {
+		return errors.New("setting ids failed")
+	}
+
+	sub.ids = ids
+	return nil
+}
+
+func (sub *subscriber) SetTimeout(timeout int) error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	rc := ecalc.ECAL_Sub_SetTimeout(sub.handle, timeout)
+	if rc == 0 {
+		return errors.New("setting QOS failed")
+	}
+
+	return nil
+}
+
+func (sub *subscriber) Dump() ([]byte, error) {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return nil, errors.New("subscriber already destroyed")
+	}
+
+	const bufferSize = 4096
+	cBuffer := C.malloc(bufferSize)
+	defer C.free(cBuffer)
+
+	bytesInDump := ecalc.ECAL_Sub_Dump(sub.handle, (uintptr)(cBuffer), bufferSize)
+	if bytesInDump <= 0 {
+		return nil, errors.New("dump failed")
+	}
+
+	dump := make([]byte, bytesInDump, bytesInDump)
+	gBuffer := (*[1 << 30]byte)(cBuffer)
+	copy(dump, gBuffer[:bytesInDump])
+	return dump, nil
+}
+
+func (sub *subscriber) receive(message Message) {
+	atomic.StoreInt64(&sub.received, time.Now().UnixNano())
+
+	sub.mutex.Lock()
+	if !sub.running {
+		sub.mutex.Unlock()
+		return
+	}
+	sub.workers.Add(1)
+	sub.mutex.Unlock()
+	defer sub.workers.Done()
+
+	if sub.handler != nil {
+		sub.handler(message)
+		return
+	}
+
+	deliverMessage(sub.outputSink, message, &sub.rejected)
+}
+
+// messageReceiver is implemented by everything registering the receive callback, i.e. subscribers in
+// callback mode and JSON subscribers.
+type messageReceiver interface {
+	receive(message Message)
+}
+
+//export goSubReceiveCallback
+func goSubReceiveCallback(cTopicName *C.char, cData *C.struct_SReceiveCallbackDataC, par unsafe.Pointer) {
+	receiver, ok := pointer.Restore(par).(messageReceiver)
+	if !ok || cData == nil {
+		return
+	}
+
+	message := Message{Content: nil,
+		Timestamp: int64(cData.time),
+		ID:        int64(cData.id),
+		Clock:     int64(cData.clock)}
+	if cData.buf != nil && cData.size > 0 {
+		message.Content = C.GoBytes(cData.buf, C.int(cData.size))
+	}
+
+	receiver.receive(message)
+}
+
+var subEventTypes = map[ecalc.Enum_SS_eCAL_Subscriber_Event]int{
+	ecalc.Sub_event_connected:    EventConnected,
+	ecalc.Sub_event_disconnected: EventDisconnected,
+	ecalc.Sub_event_dropped:      EventDropped,
+	ecalc.Sub_event_timeout:      EventTimeout,
+	ecalc.Sub_event_corrupted:    EventCorrupted,
+}
+
+//export goSubEventCallback
+func goSubEventCallback(cTopicName *C.char, cData *C.struct_SSubEventCallbackDataC, par unsafe.Pointer) {
+	sub, ok := pointer.Restore(par).(*subscriber)
+	if !ok || cData == nil {
+		return
+	}
+
+	eventType, ok := subEventTypes[ecalc.Enum_SS_eCAL_Subscriber_Event(cData._type)]
+	if !ok {
+		eventType = EventNone
+	}
+
+	deliverEvent(sub.eventSink, Event{Type: eventType,
+		Time:  int64(cData.time),
+		Clock: int64(cData.clock)})
+}
+
+func SubscriberCreate(topicName string, topicType string, topicDesc string, start bool, bufferSize int) (SubscriberIf, <-chan Message, error) {
+	if bufferSize <= 0 {
+		return nil, nil, errors.New("bufferSize must be larger than zero")
+	}
+
+	sub, err := subscriberCreate(topicName, topicType, topicDesc, bufferSize, ReceiveModePolling, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if start {
+		err := sub.Start()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return sub, sub.GetOutputChannel(), nil
+}
+
+// SubscriberCreateContext works like SubscriberCreate, but the subscriber is closed as soon as the context
+// is done.
+func SubscriberCreateContext(ctx context.Context, topicName string, topicType string, topicDesc string, start bool, bufferSize int) (SubscriberIf, <-chan Message, error) {
+	sub, subChannel, err := SubscriberCreate(topicName, topicType, topicDesc, start, bufferSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go closeOnDone(ctx, sub.(*subscriber).closeSink, sub.Close)
+	return sub, subChannel, nil
+}
+
+// SubscriberCreateCallback creates a subscriber that is notified by eCAL for every received message instead of
+// polling for it, so messages of any size are received without delay. If handler is not nil, it is called
+// for every message from the eCAL receive thread, which it blocks until it returns. Otherwise the messages are
+// delivered on the returned channel, keeping up to callbackBufferSize messages for a slow reader; further
+// messages are dropped and counted, see GetRejectedCount.
+func SubscriberCreateCallback(topicName string, topicType string, topicDesc string, start bool, handler func(Message)) (SubscriberIf, <-chan Message, error) {
+	sub, err := subscriberCreate(topicName, topicType, topicDesc, 0, ReceiveModeCallback, handler)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if start {
+		err := sub.Start()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return sub, sub.GetOutputChannel(), nil
+}
+
+// SubscriberCreateCallbackContext works like SubscriberCreateCallback, but the subscriber is closed as soon as the
+// context is done.
+func SubscriberCreateCallbackContext(ctx context.Context, topicName string, topicType string, topicDesc string, start bool, handler func(Message)) (SubscriberIf, <-chan Message, error) {
+	sub, subChannel, err := SubscriberCreateCallback(topicName, topicType, topicDesc, start, handler)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go closeOnDone(ctx, sub.(*subscriber).closeSink, sub.Close)
+	return sub, subChannel, nil
+}
+
+// SubscriberCreateAlloc creates a subscriber that receives messages of any size up to maxSize bytes without
+// pre-allocating a buffer. Larger messages are dropped and counted, see GetRejectedCount. A maxSize of zero
+// or less accepts messages of any size.
+func SubscriberCreateAlloc(topicName string, topicType string, topicDesc string, start bool, maxSize int) (SubscriberIf, <-chan Message, error) {
+	if maxSize < 0 {
+		maxSize = 0
+	}
+
+	sub, err := subscriberCreate(topicName, topicType, topicDesc, maxSize, ReceiveModeAlloc, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if start {
+		err := sub.Start()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return sub, sub.GetOutputChannel(), nil
+}
+
+// SubscriberCreateAllocContext works like SubscriberCreateAlloc, but the subscriber is closed as soon as the
+// context is done.
+func SubscriberCreateAllocContext(ctx context.Context, topicName string, topicType string, topicDesc string, start bool, maxSize int) (SubscriberIf, <-chan Message, error) {
+	sub, subChannel, err := SubscriberCreateAlloc(topicName, topicType, topicDesc, start, maxSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go closeOnDone(ctx, sub.(*subscriber).closeSink, sub.Close)
+	return sub, subChannel, nil
+}
+
+func subscriberCreate(topicName string, topicType string, topicDesc string, bufferSize int, receiveMode int, handler func(Message)) (*subscriber, error) {
+	var err error
+	if ecalc.ECAL_IsInitialized(InitSubscriber) == 0 {
+		err = Initialize(os.Args, os.Args[0], InitSubscriber)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Type and description left empty are taken from the topic if it is already known.
+	if topicType == "" {
+		topicType, _ = TopicType(topicName)
+	}
+	if topicDesc == "" {
+		topicDesc, _ = TopicDescription(topicName)
+	}
+
+	handle := ecalc.ECAL_Sub_New()
+	if handle == 0 {
+		return nil, errors.New("could not create new subscriber")
+	}
+
+	rc := ecalc.ECAL_Sub_Create(handle, topicName, topicType, topicDesc, len(topicDesc))
+	if rc == 0 {
+		return nil, errors.New("could not create new subscriber")
+	}
+
+	outputSink := make(chan Message)
+	if receiveMode == ReceiveModeCallback {
+		outputSink = make(chan Message, callbackBufferSize)
+	}
+
+	sub := subscriber{handle: handle,
+		bufferSize:  bufferSize,
+		running:     false,
+		destroyed:   false,
+		closed:      false,
+		receiveMode: receiveMode,
+		handler:     handler,
+		reference:   nil,
+		done:        make(chan struct{}),
+		closeSink:   make(chan struct{}),
+		workers:     &sync.WaitGroup{},
+		outputSink:  outputSink,
+		eventSink:   make(chan Event, eventBufferSize),
+		topicName:   topicName,
+		topicType:   topicType,
+		topicDesc:   topicDesc,
+		ids:         make([]int64, 0),
+		timeout:     0,
+		mutex:       &sync.Mutex{}}
+	sub.reference = pointer.Save(&sub)
+
+	for eventType := range subEventTypes {
+		rc = ecalc.ECAL_Sub_AddEventCallbackC(handle, eventType, subEventCallbackPtr(), uintptr(sub.reference))
+		if rc == 0 {
+			sub.Destroy()
+			return nil, errors.New("adding event callback failed")
+		}
+	}
+
+	return &sub, nil
+}
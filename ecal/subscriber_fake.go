@@ -0,0 +1,458 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type memorySubscriber struct {
+	handle      uintptr
+	bufferSize  int
+	rejected    int64
+	received    int64
+	running     bool
+	destroyed   bool
+	closed      bool
+	receiveMode int
+	handler     func(Message)
+	done        chan struct{}
+	closeSink   chan struct{}
+	workers     *sync.WaitGroup
+	outputSink  chan Message
+	eventSink   chan Event
+	topicName   string
+	topicType   string
+	topicDesc   string
+	ids         []int64
+	timeout     int
+	mutex       *sync.Mutex
+
+	// The queue is filled while the registry is locked, so it is guarded by its own mutex.
+	receiving   bool
+	clock       int64
+	qos         ReaderQOS
+	filter      []int64
+	queue       []Message
+	queueSignal chan struct{}
+	queueMutex  *sync.Mutex
+}
+
+func (sub *memorySubscriber) Start() error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	if sub.running {
+		return nil
+	}
+	sub.running = true
+	sub.done = make(chan struct{})
+
+	sub.queueMutex.Lock()
+	sub.receiving = true
+	sub.queueMutex.Unlock()
+
+	sub.workers.Add(1)
+	go sub.deliver(sub.done)
+	return nil
+}
+
+// deliver hands the queued messages to the handler or the output channel until done is closed.
+func (sub *memorySubscriber) deliver(done chan struct{}) {
+	defer sub.workers.Done()
+
+	for {
+		select {
+		case <-sub.queueSignal:
+		case <-done:
+			return
+		}
+
+		for {
+			sub.queueMutex.Lock()
+			if len(sub.queue) == 0 {
+				sub.queueMutex.Unlock()
+				break
+			}
+			message := sub.queue[0]
+			sub.queue = sub.queue[1:]
+			sub.queueMutex.Unlock()
+
+			if sub.handler != nil {
+				sub.handler(message)
+				continue
+			}
+			if sub.receiveMode == ReceiveModeCallback {
+				deliverMessage(sub.outputSink, message, &sub.rejected)
+				continue
+			}
+
+			select {
+			case sub.outputSink <- message:
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+func (sub *memorySubscriber) enqueue(message Message, writerQOS WriterQOS) {
+	if sub.bufferSize > 0 && len(message.Content) > sub.bufferSize {
+		atomic.AddInt64(&sub.rejected, 1)
+		return
+	}
+
+	sub.queueMutex.Lock()
+	defer sub.queueMutex.Unlock()
+
+	if !sub.receiving || !idsMatch(sub.filter, message.ID) {
+		return
+	}
+
+	content := make([]byte, len(message.Content))
+	copy(content, message.Content)
+	message.Content = content
+	atomic.StoreInt64(&sub.received, time.Now().UnixNano())
+	sub.queue = append(sub.queue, message)
+	sub.clock++
+
+	keepAll := writerQOS.HistoryKind == KeepAllHistoryQOS && sub.qos.HistoryKind == KeepAllHistoryQOS
+	if !keepAll && len(sub.queue) > memoryHistoryDepth {
+		dropped := sub.queue[0]
+		sub.queue = sub.queue[1:]
+		deliverEvent(sub.eventSink, Event{Type: EventDropped,
+			Time:  memoryTime(),
+			Clock: dropped.Clock})
+	}
+
+	select {
+	case sub.queueSignal <- struct{}{}:
+	default:
+	}
+}
+
+func idsMatch(ids []int64, id int64) bool {
+	if len(ids) == 0 {
+		return true
+	}
+
+	for _, filterID := range ids {
+		if filterID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (sub *memorySubscriber) Stop() error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	if !sub.running {
+		return nil
+	}
+
+	close(sub.done)
+	sub.running = false
+
+	sub.queueMutex.Lock()
+	sub.receiving = false
+	sub.queue = nil
+	sub.queueMutex.Unlock()
+	return nil
+}
+
+func (sub *memorySubscriber) Destroy() error {
+	if sub.running {
+		sub.Stop()
+	}
+
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	registry.removeSubscriber(sub)
+	sub.destroyed = true
+	return nil
+}
+
+// Close stops the subscriber, waits for pending deliveries to finish, destroys it and closes the output
+// channel. It must not be called from a message handler.
+func (sub *memorySubscriber) Close() error {
+	sub.mutex.Lock()
+	if sub.closed {
+		sub.mutex.Unlock()
+		return errors.New("subscriber already closed")
+	}
+	sub.closed = true
+	sub.mutex.Unlock()
+
+	if !sub.IsDestroyed() {
+		sub.Stop()
+	}
+	sub.workers.Wait()
+
+	var err error
+	if !sub.IsDestroyed() {
+		err = sub.Destroy()
+	}
+
+	close(sub.outputSink)
+	close(sub.closeSink)
+	return err
+}
+
+func (sub *memorySubscriber) IsStopped() bool {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	return !sub.running
+}
+
+func (sub *memorySubscriber) IsDestroyed() bool {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	return sub.destroyed
+}
+
+func (sub *memorySubscriber) GetHandle() uintptr {
+	return sub.handle
+}
+
+func (sub *memorySubscriber) GetBufferSize() int {
+	return sub.bufferSize
+}
+
+func (sub *memorySubscriber) GetRejectedCount() int64 {
+	return atomic.LoadInt64(&sub.rejected)
+}
+
+func (sub *memorySubscriber) GetLastReceiveTime() time.Time {
+	received := atomic.LoadInt64(&sub.received)
+	if received == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, received)
+}
+
+func (sub *memorySubscriber) GetOutputChannel() <-chan Message {
+	return sub.outputSink
+}
+
+func (sub *memorySubscriber) GetEventChannel() <-chan Event {
+	return sub.eventSink
+}
+
+func (sub *memorySubscriber) GetTopic() string {
+	return sub.topicName
+}
+
+func (sub *memorySubscriber) GetType() string {
+	return sub.topicType
+}
+
+func (sub *memorySubscriber) GetDescription() string {
+	return sub.topicDesc
+}
+
+func (sub *memorySubscriber) GetQoS() (ReaderQOS, error) {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return ReaderQOS{KeepLastHistoryQOS, BestEffortReliability}, errors.New("subscriber already destroyed")
+	}
+
+	sub.queueMutex.Lock()
+	defer sub.queueMutex.Unlock()
+
+	return sub.qos, nil
+}
+
+func (sub *memorySubscriber) GetIDs() []int64 {
+	return sub.ids
+}
+
+func (sub *memorySubscriber) GetTimeout() int {
+	return sub.timeout
+}
+
+func (sub *memorySubscriber) GetReceiveMode() int {
+	return sub.receiveMode
+}
+
+func (sub *memorySubscriber) SetQoS(qos ReaderQOS) error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	sub.queueMutex.Lock()
+	defer sub.queueMutex.Unlock()
+
+	sub.qos = qos
+	return nil
+}
+
+func (sub *memorySubscriber) SetIDs(ids []int64) error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	sub.queueMutex.Lock()
+	defer sub.queueMutex.Unlock()
+
+	sub.ids = ids
+	sub.filter = ids
+	return nil
+}
+
+func (sub *memorySubscriber) SetTimeout(timeout int) error {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.destroyed {
+		return errors.New("subscriber already destroyed")
+	}
+
+	sub.timeout = timeout
+	return nil
+}
+
+func (sub *memorySubscriber) Dump() ([]byte, error) {
+	return nil, errors.New("dump not supported by the in-memory backend")
+}
+
+func SubscriberCreate(topicName string, topicType string, topicDesc string, start bool, bufferSize int) (SubscriberIf, <-chan Message, error) {
+	if bufferSize <= 0 {
+		return nil, nil, errors.New("bufferSize must be larger than zero")
+	}
+
+	return subscriberCreateStarted(topicName, topicType, topicDesc, start, bufferSize, ReceiveModePolling, nil)
+}
+
+func SubscriberCreateContext(ctx context.Context, topicName string, topicType string, topicDesc string, start bool, bufferSize int) (SubscriberIf, <-chan Message, error) {
+	sub, subChannel, err := SubscriberCreate(topicName, topicType, topicDesc, start, bufferSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go closeOnDone(ctx, sub.(*memorySubscriber).closeSink, sub.Close)
+	return sub, subChannel, nil
+}
+
+func SubscriberCreateCallback(topicName string, topicType string, topicDesc string, start bool, handler func(Message)) (SubscriberIf, <-chan Message, error) {
+	return subscriberCreateStarted(topicName, topicType, topicDesc, start, 0, ReceiveModeCallback, handler)
+}
+
+// SubscriberCreateCallbackContext works like SubscriberCreateCallback, but the subscriber is closed as soon as the
+// context is done.
+func SubscriberCreateCallbackContext(ctx context.Context, topicName string, topicType string, topicDesc string, start bool, handler func(Message)) (SubscriberIf, <-chan Message, error) {
+	sub, subChannel, err := SubscriberCreateCallback(topicName, topicType, topicDesc, start, handler)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go closeOnDone(ctx, sub.(*memorySubscriber).closeSink, sub.Close)
+	return sub, subChannel, nil
+}
+
+func SubscriberCreateAlloc(topicName string, topicType string, topicDesc string, start bool, maxSize int) (SubscriberIf, <-chan Message, error) {
+	if maxSize < 0 {
+		maxSize = 0
+	}
+
+	return subscriberCreateStarted(topicName, topicType, topicDesc, start, maxSize, ReceiveModeAlloc, nil)
+}
+
+// SubscriberCreateAllocContext works like SubscriberCreateAlloc, but the subscriber is closed as soon as the
+// context is done.
+func SubscriberCreateAllocContext(ctx context.Context, topicName string, topicType string, topicDesc string, start bool, maxSize int) (SubscriberIf, <-chan Message, error) {
+	sub, subChannel, err := SubscriberCreateAlloc(topicName, topicType, topicDesc, start, maxSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go closeOnDone(ctx, sub.(*memorySubscriber).closeSink, sub.Close)
+	return sub, subChannel, nil
+}
+
+func subscriberCreateStarted(topicName string, topicType string, topicDesc string, start bool, bufferSize int, receiveMode int, handler func(Message)) (SubscriberIf, <-chan Message, error) {
+	if !registry.isInitialized(InitSubscriber) {
+		err := Initialize(nil, "", InitSubscriber)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if topicType == "" {
+		topicType, _ = TopicType(topicName)
+	}
+	if topicDesc == "" {
+		topicDesc, _ = TopicDescription(topicName)
+	}
+
+	outputSink := make(chan Message)
+	if receiveMode == ReceiveModeCallback {
+		outputSink = make(chan Message, callbackBufferSize)
+	}
+
+	sub := &memorySubscriber{handle: 0,
+		bufferSize:  bufferSize,
+		rejected:    0,
+		received:    0,
+		running:     false,
+		destroyed:   false,
+		closed:      false,
+		receiveMode: receiveMode,
+		handler:     handler,
+		done:        make(chan struct{}),
+		closeSink:   make(chan struct{}),
+		workers:     &sync.WaitGroup{},
+		outputSink:  outputSink,
+		eventSink:   make(chan Event, eventBufferSize),
+		topicName:   topicName,
+		topicType:   topicType,
+		topicDesc:   topicDesc,
+		ids:         make([]int64, 0),
+		timeout:     0,
+		mutex:       &sync.Mutex{},
+		receiving:   false,
+		clock:       0,
+		qos:         ReaderQOS{KeepLastHistoryQOS, BestEffortReliability},
+		filter:      nil,
+		queue:       nil,
+		queueSignal: make(chan struct{}, 1),
+		queueMutex:  &sync.Mutex{}}
+	registry.addSubscriber(sub)
+
+	if start {
+		err := sub.Start()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return sub, sub.GetOutputChannel(), nil
+}
@@ -0,0 +1,283 @@
+package ecal
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// supervisorMissedPolls is how many polls in a row a registered node must be missing from before it is
+// restarted, as a single snapshot may miss a process whose registration is late.
+const supervisorMissedPolls = 2
+
+const (
+	NodeStarted     = iota
+	NodeRestarted   = iota
+	NodeStartFailed = iota
+)
+
+// Node is a process started and kept running by a Supervisor.
+type Node struct {
+	Name    string
+	Path    string
+	Args    []string
+	WorkDir string
+	Options StartOptions
+}
+
+// NodeEvent reports a node that was started or restarted, or could not be started.
+type NodeEvent struct {
+	Type      int
+	Name      string
+	ProcessID int
+	Err       error
+}
+
+type supervisedNode struct {
+	node        Node
+	processID   int
+	processName string
+	unitName    string
+	registered  bool
+	missed      int
+	started     time.Time
+}
+
+// reportedBy returns whether the node's process is among the processes. Once the node registered, the process
+// must keep its name, as the ID of a terminated process may be reused by another one.
+func (supervised *supervisedNode) reportedBy(processes map[int]MonitoredProcess) bool {
+	process, ok := processes[supervised.processID]
+	if !ok {
+		return false
+	}
+	return !supervised.registered ||
+		(process.ProcessName == supervised.processName && process.UnitName == supervised.unitName)
+}
+
+// Supervisor starts nodes on this host and restarts them once they disappear from the monitoring data.
+// Nodes that do not register with eCAL within the startup timeout are stopped and restarted as well. Nodes that
+// disappeared are not stopped, as the ID of their process may already belong to another process.
+type Supervisor struct {
+	interval       time.Duration
+	startupTimeout time.Duration
+	hostName       string
+	nodes          []*supervisedNode
+	eventSink      chan NodeEvent
+	errorSink      chan error
+	done           chan struct{}
+	finished       chan struct{}
+	started        bool
+	closed         bool
+	once           *sync.Once
+	mutex          *sync.Mutex
+}
+
+func NewSupervisor(interval time.Duration, startupTimeout time.Duration) (*Supervisor, error) {
+	if interval <= 0 {
+		return nil, errors.New("interval must be larger than zero")
+	}
+	if startupTimeout <= 0 {
+		return nil, errors.New("startup timeout must be larger than zero")
+	}
+
+	details, err := ProcessInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Supervisor{interval: interval,
+		startupTimeout: startupTimeout,
+		hostName:       details.HostName,
+		nodes:          make([]*supervisedNode, 0),
+		eventSink:      make(chan NodeEvent, eventBufferSize),
+		errorSink:      make(chan error, errorBufferSize),
+		done:           make(chan struct{}),
+		finished:       make(chan struct{}),
+		started:        false,
+		closed:         false,
+		once:           &sync.Once{},
+		mutex:          &sync.Mutex{}}, nil
+}
+
+// Add registers a node. Nodes added after Start are started immediately. Nodes must not block on start, as
+// the supervisor waits for them.
+func (supervisor *Supervisor) Add(node Node) error {
+	if node.Options.Block {
+		return errors.New("supervised nodes must not block on start")
+	}
+
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	if supervisor.closed {
+		return errors.New("supervisor already closed")
+	}
+
+	supervised := &supervisedNode{node: node}
+	supervisor.nodes = append(supervisor.nodes, supervised)
+	if supervisor.started {
+		supervisor.start(supervised, NodeStarted)
+	}
+	return nil
+}
+
+// Start starts all nodes and begins watching them.
+func (supervisor *Supervisor) Start() error {
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	if supervisor.closed {
+		return errors.New("supervisor already closed")
+	}
+	if supervisor.started {
+		return errors.New("supervisor already started")
+	}
+	supervisor.started = true
+
+	for _, supervised := range supervisor.nodes {
+		supervisor.start(supervised, NodeStarted)
+	}
+
+	go supervisor.watch()
+	return nil
+}
+
+func (supervisor *Supervisor) start(supervised *supervisedNode, eventType int) {
+	node := supervised.node
+	processID, err := StartProcess(node.Path, node.Args, node.WorkDir, node.Options)
+	supervised.processID = processID
+	supervised.processName = ""
+	supervised.unitName = ""
+	supervised.registered = false
+	supervised.missed = 0
+	supervised.started = time.Now()
+
+	if err != nil {
+		eventType = NodeStartFailed
+	}
+	supervisor.deliver(NodeEvent{Type: eventType, Name: node.Name, ProcessID: processID, Err: err})
+}
+
+func (supervisor *Supervisor) watch() {
+	defer close(supervisor.finished)
+
+	ticker := time.NewTicker(supervisor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-supervisor.done:
+			return
+		}
+
+		snapshot, err := Monitoring()
+		if err != nil {
+			select {
+			case supervisor.errorSink <- err:
+			default:
+			}
+			continue
+		}
+
+		processes := supervisor.hostProcesses(snapshot)
+
+		supervisor.mutex.Lock()
+		for _, supervised := range supervisor.nodes {
+			switch {
+			case supervised.reportedBy(processes):
+				process := processes[supervised.processID]
+				supervised.processName = process.ProcessName
+				supervised.unitName = process.UnitName
+				supervised.registered = true
+				supervised.missed = 0
+			case supervised.registered:
+				supervised.missed++
+				if supervised.missed < supervisorMissedPolls {
+					continue
+				}
+				supervisor.start(supervised, NodeRestarted)
+			case time.Since(supervised.started) > supervisor.startupTimeout:
+				if supervised.processID != 0 {
+					StopProcessID(supervised.processID)
+				}
+				supervisor.start(supervised, NodeRestarted)
+			}
+		}
+		supervisor.mutex.Unlock()
+	}
+}
+
+// hostProcesses returns the processes of this host by ID.
+func (supervisor *Supervisor) hostProcesses(snapshot MonitoringSnapshot) map[int]MonitoredProcess {
+	processes := make(map[int]MonitoredProcess)
+	for _, process := range snapshot.Processes {
+		if process.HostName == supervisor.hostName {
+			processes[process.ProcessID] = process
+		}
+	}
+	return processes
+}
+
+func (supervisor *Supervisor) deliver(event NodeEvent) {
+	select {
+	case supervisor.eventSink <- event:
+	default:
+	}
+}
+
+// GetEventChannel returns the channel reporting node starts. Events are dropped while the channel is full.
+func (supervisor *Supervisor) GetEventChannel() <-chan NodeEvent {
+	return supervisor.eventSink
+}
+
+// GetErrorChannel returns the channel reporting failed polls. Errors are dropped while the channel is full.
+func (supervisor *Supervisor) GetErrorChannel() <-chan error {
+	return supervisor.errorSink
+}
+
+// ProcessIDs returns the current process ID of every node by name.
+func (supervisor *Supervisor) ProcessIDs() map[string]int {
+	supervisor.mutex.Lock()
+	defer supervisor.mutex.Unlock()
+
+	processIDs := make(map[string]int, len(supervisor.nodes))
+	for _, supervised := range supervisor.nodes {
+		processIDs[supervised.node.Name] = supervised.processID
+	}
+	return processIDs
+}
+
+// Close stops watching and stops all nodes that are still starting or reported by the monitoring data.
+func (supervisor *Supervisor) Close() error {
+	err := errors.New("supervisor already closed")
+	supervisor.once.Do(func() {
+		close(supervisor.done)
+
+		supervisor.mutex.Lock()
+		started := supervisor.started
+		supervisor.closed = true
+		supervisor.mutex.Unlock()
+		if started {
+			<-supervisor.finished
+		}
+
+		snapshot, monitoringErr := Monitoring()
+		processes := supervisor.hostProcesses(snapshot)
+
+		supervisor.mutex.Lock()
+		defer supervisor.mutex.Unlock()
+
+		err = monitoringErr
+		for _, supervised := range supervisor.nodes {
+			if supervised.processID == 0 || (supervised.registered && !supervised.reportedBy(processes)) {
+				continue
+			}
+			stopErr := StopProcessID(supervised.processID)
+			if stopErr != nil && err == nil {
+				err = stopErr
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,145 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSupervisorArguments(t *testing.T) {
+	tests := []struct {
+		interval       time.Duration
+		startupTimeout time.Duration
+		valid          bool
+	}{
+		{time.Second, time.Second, true},
+		{0, time.Second, false},
+		{time.Second, 0, false},
+		{time.Second, -time.Second, false},
+	}
+
+	for _, test := range tests {
+		supervisor, err := NewSupervisor(test.interval, test.startupTimeout)
+		if (err == nil) != test.valid {
+			t.Errorf("NewSupervisor(%v, %v) returned error %v", test.interval, test.startupTimeout, err)
+		}
+		if supervisor != nil {
+			supervisor.Close()
+		}
+	}
+}
+
+func TestSupervisorAdd(t *testing.T) {
+	supervisor, err := NewSupervisor(time.Second, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = supervisor.Add(Node{Name: "blocking", Path: "sleep", Options: StartOptions{Block: true}})
+	if err == nil {
+		t.Error("blocking node added")
+	}
+	err = supervisor.Add(Node{Name: "node", Path: "sleep"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = supervisor.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if supervisor.Add(Node{Name: "late", Path: "sleep"}) == nil {
+		t.Error("node added after Close")
+	}
+	if supervisor.Start() == nil {
+		t.Error("supervisor started after Close")
+	}
+	if supervisor.Close() == nil {
+		t.Error("second Close succeeded")
+	}
+}
+
+func receiveNodeEvent(t *testing.T, supervisor *Supervisor) NodeEvent {
+	t.Helper()
+
+	select {
+	case event := <-supervisor.GetEventChannel():
+		return event
+	case <-time.After(testTimeout):
+		t.Fatal("no node event received")
+	}
+	return NodeEvent{}
+}
+
+func TestSupervisorRestart(t *testing.T) {
+	supervisor, err := NewSupervisor(10*time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = supervisor.Add(Node{Name: "node", Path: "/usr/bin/node", Args: []string{"--verbose"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = supervisor.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := receiveNodeEvent(t, supervisor)
+	if event.Type != NodeStarted || event.Name != "node" || event.Err != nil {
+		t.Fatalf("received %+v, want a started node", event)
+	}
+	processID := event.ProcessID
+
+	deadline := time.Now().Add(testTimeout)
+	for {
+		supervisor.mutex.Lock()
+		registered := supervisor.nodes[0].registered
+		supervisor.mutex.Unlock()
+		if registered {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("node not registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The node terminates and its process ID is reused by another process, which must be left alone.
+	registry.mutex.Lock()
+	registry.processes[processID] = MonitoredProcess{HostName: supervisor.hostName,
+		ProcessID:   processID,
+		ProcessName: "/usr/bin/other",
+		UnitName:    "other"}
+	registry.mutex.Unlock()
+	defer StopProcessID(processID)
+
+	event = receiveNodeEvent(t, supervisor)
+	if event.Type != NodeRestarted || event.Name != "node" || event.Err != nil {
+		t.Fatalf("received %+v, want a restarted node", event)
+	}
+	if event.ProcessID == processID {
+		t.Fatal("node restarted with the ID of the other process")
+	}
+	if ids := supervisor.ProcessIDs(); ids["node"] != event.ProcessID {
+		t.Errorf("process IDs %v, want node %d", ids, event.ProcessID)
+	}
+
+	err = supervisor.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry.mutex.Lock()
+	_, otherRunning := registry.processes[processID]
+	_, nodeRunning := registry.processes[event.ProcessID]
+	registry.mutex.Unlock()
+	if !otherRunning {
+		t.Error("process reusing the ID of the terminated node was stopped")
+	}
+	if nodeRunning {
+		t.Error("restarted node not stopped by Close")
+	}
+}
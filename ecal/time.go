@@ -0,0 +1,28 @@
+package ecal
+
+import "time"
+
+// TimeState is the state of the eCAL time synchronization.
+type TimeState struct {
+	// Name is the name of the eCAL time plugin.
+	Name         string
+	Synchronized bool
+	Master       bool
+	Error        int
+	Status       string
+}
+
+// TimeFromTimestamp converts an eCAL timestamp in microseconds, e.g. Message.Timestamp, to a time.Time.
+func TimeFromTimestamp(timestamp int64) time.Time {
+	return time.UnixMicro(timestamp)
+}
+
+// TimestampFromTime converts a time.Time to an eCAL timestamp in microseconds.
+func TimestampFromTime(t time.Time) int64 {
+	return t.UnixMicro()
+}
+
+// Time returns the timestamp of the message as time.Time.
+func (message Message) Time() time.Time {
+	return TimeFromTimestamp(message.Timestamp)
+}
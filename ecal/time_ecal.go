@@ -0,0 +1,57 @@
+//go:build !ecalfake
+
+package ecal
+
+import "C"
+import (
+	"os"
+	"time"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+)
+
+// initializeTimeSync loads the eCAL time plugin if needed. eCAL falls back to the system time if this fails.
+func initializeTimeSync() {
+	if ecalc.ECAL_IsInitialized(InitTimeSync) == 0 {
+		Initialize(os.Args, os.Args[0], InitTimeSync)
+	}
+}
+
+// Now returns the current eCAL time, which is controlled by the time master, e.g. a replay.
+func Now() time.Time {
+	initializeTimeSync()
+	return time.Unix(0, ecalc.ECAL_Time_GetNanoSeconds())
+}
+
+// Sleep pauses for the duration measured in eCAL time.
+func Sleep(duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+
+	initializeTimeSync()
+	ecalc.ECAL_Time_SleepForNanoseconds(duration.Nanoseconds())
+}
+
+func TimeStatus() TimeState {
+	initializeTimeSync()
+
+	var cError C.int
+	var cStatus unsafe.Pointer
+	statusLen := ecalc.ECAL_Time_GetStatus((*int)(unsafe.Pointer(&cError)), (*string)(unsafe.Pointer(&cStatus)), ecalc.ECAL_ALLOCATE_4ME)
+
+	state := TimeState{Name: allocatedString(ecalc.ECAL_Time_GetName),
+		Synchronized: ecalc.ECAL_Time_IsTimeSynchronized() != 0,
+		Master:       ecalc.ECAL_Time_IsTimeMaster() != 0,
+		Error:        int(cError),
+		Status:       ""}
+	if cStatus != nil {
+		if statusLen > 0 {
+			state.Status = C.GoStringN((*C.char)(cStatus), C.int(statusLen))
+		}
+		ecalc.ECAL_FreeMem(uintptr(cStatus))
+	}
+
+	return state
+}
@@ -0,0 +1,225 @@
+//go:build !ecalfake
+
+package ecal
+
+import "C"
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+	"github.com/mattn/go-pointer"
+)
+
+type timer struct {
+	handle     uintptr
+	running    bool
+	destroyed  bool
+	oneShot    bool
+	ticking    bool
+	generation int
+	handler    func(time.Time)
+	tickSink   chan time.Time
+	reference  unsafe.Pointer
+	// eCAL waits for a running callback when stopping the timer, so starting and stopping is serialized by
+	// controlMutex while the callback only takes mutex.
+	controlMutex *sync.Mutex
+	mutex        *sync.Mutex
+}
+
+func (tm *timer) Start(period time.Duration, oneShot bool) error {
+	periodMs := int(period / time.Millisecond)
+	if periodMs <= 0 {
+		return errors.New("period must be at least one millisecond")
+	}
+
+	tm.controlMutex.Lock()
+	defer tm.controlMutex.Unlock()
+
+	if tm.IsDestroyed() {
+		return errors.New("timer already destroyed")
+	}
+
+	tm.stop()
+
+	tm.mutex.Lock()
+	tm.running = true
+	tm.oneShot = oneShot
+	tm.mutex.Unlock()
+
+	rc := ecalc.ECAL_Timer_Start(tm.handle, periodMs, timerCallbackPtr(), periodMs, uintptr(tm.reference))
+	if rc == 0 {
+		tm.stop()
+		return errors.New("starting timer failed")
+	}
+
+	return nil
+}
+
+func (tm *timer) Stop() error {
+	// eCAL would wait for the running handler to return, which deadlocks if the handler stops its own timer. The
+	// handler ignores further ticks from now on and the eCAL timer is stopped in the background.
+	tm.mutex.Lock()
+	if tm.ticking && !tm.destroyed {
+		tm.running = false
+		go tm.stopGeneration(tm.generation)
+		tm.mutex.Unlock()
+		return nil
+	}
+	tm.mutex.Unlock()
+
+	tm.controlMutex.Lock()
+	defer tm.controlMutex.Unlock()
+
+	if tm.IsDestroyed() {
+		return errors.New("timer already destroyed")
+	}
+
+	tm.stop()
+	return nil
+}
+
+// stop stops the timer, ticks of the current run are ignored from now on. controlMutex must be held.
+func (tm *timer) stop() {
+	tm.mutex.Lock()
+	tm.running = false
+	tm.generation++
+	tm.mutex.Unlock()
+
+	ecalc.ECAL_Timer_Stop(tm.handle)
+}
+
+// stopGeneration stops a one-shot timer after its tick unless it was restarted in the meantime.
+func (tm *timer) stopGeneration(generation int) {
+	tm.controlMutex.Lock()
+	defer tm.controlMutex.Unlock()
+
+	tm.mutex.Lock()
+	current := tm.generation == generation && !tm.destroyed
+	tm.mutex.Unlock()
+
+	if current {
+		tm.stop()
+	}
+}
+
+func (tm *timer) Destroy() error {
+	tm.controlMutex.Lock()
+	defer tm.controlMutex.Unlock()
+
+	if tm.IsDestroyed() {
+		return errors.New("timer already destroyed")
+	}
+
+	tm.stop()
+
+	rc := ecalc.ECAL_Timer_Destroy(tm.handle)
+	if rc == 0 {
+		return errors.New("could not destroy timer")
+	}
+
+	pointer.Unref(tm.reference)
+	tm.reference = nil
+
+	tm.mutex.Lock()
+	tm.destroyed = true
+	tm.mutex.Unlock()
+	return nil
+}
+
+func (tm *timer) IsStopped() bool {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	return !tm.running
+}
+
+func (tm *timer) IsDestroyed() bool {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	return tm.destroyed
+}
+
+func (tm *timer) GetHandle() uintptr {
+	return tm.handle
+}
+
+// GetTickChannel returns the channel delivering the eCAL time of each tick if no handler was given. Ticks are
+// dropped while the channel is full.
+func (tm *timer) GetTickChannel() <-chan time.Time {
+	return tm.tickSink
+}
+
+func (tm *timer) tick() {
+	tm.mutex.Lock()
+	if !tm.running {
+		tm.mutex.Unlock()
+		return
+	}
+	if tm.oneShot {
+		tm.running = false
+		go tm.stopGeneration(tm.generation)
+	}
+	tm.ticking = tm.handler != nil
+	tm.mutex.Unlock()
+
+	now := Now()
+	if tm.handler != nil {
+		tm.handler(now)
+
+		tm.mutex.Lock()
+		tm.ticking = false
+		tm.mutex.Unlock()
+		return
+	}
+
+	select {
+	case tm.tickSink <- now:
+	default:
+	}
+}
+
+//export goTimerCallback
+func goTimerCallback(par unsafe.Pointer) {
+	tm, ok := pointer.Restore(par).(*timer)
+	if !ok {
+		return
+	}
+
+	tm.tick()
+}
+
+// TimerCreate creates a stopped timer driven by the eCAL time. If handler is not nil, it is called for every
+// tick from the eCAL timer thread; otherwise the ticks are delivered on the tick channel.
+func TimerCreate(handler func(time.Time)) (TimerIf, error) {
+	if ecalc.ECAL_IsInitialized(InitTimeSync) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitTimeSync)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	handle := ecalc.ECAL_Timer_Create()
+	if handle == 0 {
+		return nil, errors.New("could not create new timer")
+	}
+
+	tm := &timer{handle: handle,
+		running:      false,
+		destroyed:    false,
+		oneShot:      false,
+		ticking:      false,
+		generation:   0,
+		handler:      handler,
+		tickSink:     make(chan time.Time, 1),
+		reference:    nil,
+		controlMutex: &sync.Mutex{},
+		mutex:        &sync.Mutex{}}
+	tm.reference = pointer.Save(tm)
+
+	return tm, nil
+}
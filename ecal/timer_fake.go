@@ -0,0 +1,142 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type memoryTimer struct {
+	handle    uintptr
+	running   bool
+	destroyed bool
+	done      chan struct{}
+	handler   func(time.Time)
+	tickSink  chan time.Time
+	mutex     *sync.Mutex
+}
+
+func (tm *memoryTimer) Start(period time.Duration, oneShot bool) error {
+	if period < time.Millisecond {
+		return errors.New("period must be at least one millisecond")
+	}
+
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if tm.destroyed {
+		return errors.New("timer already destroyed")
+	}
+
+	if tm.running {
+		close(tm.done)
+	}
+	tm.running = true
+	tm.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				if oneShot {
+					tm.mutex.Lock()
+					if tm.done == done {
+						tm.running = false
+					}
+					tm.mutex.Unlock()
+				}
+				tm.tick(now)
+				if oneShot {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}(tm.done)
+
+	return nil
+}
+
+func (tm *memoryTimer) tick(now time.Time) {
+	if tm.handler != nil {
+		tm.handler(now)
+		return
+	}
+
+	select {
+	case tm.tickSink <- now:
+	default:
+	}
+}
+
+func (tm *memoryTimer) Stop() error {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if tm.destroyed {
+		return errors.New("timer already destroyed")
+	}
+
+	if tm.running {
+		close(tm.done)
+	}
+	tm.running = false
+	return nil
+}
+
+func (tm *memoryTimer) Destroy() error {
+	err := tm.Stop()
+	if err != nil {
+		return err
+	}
+
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	tm.destroyed = true
+	return nil
+}
+
+func (tm *memoryTimer) IsStopped() bool {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	return !tm.running
+}
+
+func (tm *memoryTimer) IsDestroyed() bool {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	return tm.destroyed
+}
+
+func (tm *memoryTimer) GetHandle() uintptr {
+	return tm.handle
+}
+
+func (tm *memoryTimer) GetTickChannel() <-chan time.Time {
+	return tm.tickSink
+}
+
+// TimerCreate creates a stopped timer driven by the wall clock.
+func TimerCreate(handler func(time.Time)) (TimerIf, error) {
+	registry.mutex.Lock()
+	registry.handles++
+	handle := registry.handles
+	registry.mutex.Unlock()
+
+	return &memoryTimer{handle: handle,
+		running:   false,
+		destroyed: false,
+		done:      make(chan struct{}),
+		handler:   handler,
+		tickSink:  make(chan time.Time, 1),
+		mutex:     &sync.Mutex{}}, nil
+}
@@ -0,0 +1,231 @@
+package ecal
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Codec converts values of type T to and from the message content of a topic.
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(content []byte) (T, error)
+	TopicType() string
+}
+
+// DescribedCodec is implemented by codecs that also provide the topic description passed to eCAL.
+type DescribedCodec interface {
+	TopicDescription() string
+}
+
+// Typed is a decoded message.
+type Typed[T any] struct {
+	Value     T
+	Timestamp int64
+}
+
+// typedErrorBufferSize is the number of decode errors kept for a slow reader. Further errors are dropped.
+const typedErrorBufferSize = 16
+
+type Publisher[T any] struct {
+	pub       PublisherIf
+	codec     Codec[T]
+	closeSink chan struct{}
+	once      *sync.Once
+}
+
+// NewPublisher creates a started publisher that encodes values with the given codec.
+func NewPublisher[T any](topicName string, codec Codec[T]) (*Publisher[T], error) {
+	if codec == nil {
+		return nil, errors.New("no codec given")
+	}
+
+	topicDesc := ""
+	if describedCodec, ok := codec.(DescribedCodec); ok {
+		topicDesc = describedCodec.TopicDescription()
+	}
+
+	pub, _, err := PublisherCreate(topicName, codec.TopicType(), topicDesc, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Publisher[T]{pub: pub,
+		codec:     codec,
+		closeSink: make(chan struct{}),
+		once:      &sync.Once{}}, nil
+}
+
+// NewPublisherContext works like NewPublisher, but the publisher is closed as soon as the context is done.
+func NewPublisherContext[T any](ctx context.Context, topicName string, codec Codec[T]) (*Publisher[T], error) {
+	typedPub, err := NewPublisher(topicName, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	go closeOnDone(ctx, typedPub.closeSink, typedPub.Close)
+	return typedPub, nil
+}
+
+// Send encodes the value and publishes it synchronously.
+func (typedPub *Publisher[T]) Send(ctx context.Context, value T) error {
+	content, err := typedPub.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = typedPub.pub.Send(ctx, Message{Content: content, Timestamp: -1})
+	return err
+}
+
+func (typedPub *Publisher[T]) Publisher() PublisherIf {
+	return typedPub.pub
+}
+
+func (typedPub *Publisher[T]) Destroy() error {
+	return typedPub.pub.Destroy()
+}
+
+func (typedPub *Publisher[T]) Close() error {
+	typedPub.once.Do(func() {
+		close(typedPub.closeSink)
+	})
+	return typedPub.pub.Close()
+}
+
+type Subscriber[T any] struct {
+	sub        SubscriberIf
+	codec      Codec[T]
+	outputSink chan Typed[T]
+	errorSink  chan error
+	done       chan struct{}
+	finished   chan struct{}
+	closeSink  chan struct{}
+	once       *sync.Once
+	closeOnce  *sync.Once
+}
+
+// NewSubscriber creates a started subscriber that decodes all messages with the given codec. Messages that
+// cannot be decoded are skipped and the error is reported on the error channel.
+func NewSubscriber[T any](topicName string, codec Codec[T]) (*Subscriber[T], error) {
+	if codec == nil {
+		return nil, errors.New("no codec given")
+	}
+
+	topicDesc := ""
+	if describedCodec, ok := codec.(DescribedCodec); ok {
+		topicDesc = describedCodec.TopicDescription()
+	}
+
+	sub, messageSource, err := SubscriberCreateCallback(topicName, codec.TopicType(), topicDesc, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	typedSub := &Subscriber[T]{sub: sub,
+		codec:      codec,
+		outputSink: make(chan Typed[T]),
+		errorSink:  make(chan error, typedErrorBufferSize),
+		done:       make(chan struct{}),
+		finished:   make(chan struct{}),
+		closeSink:  make(chan struct{}),
+		once:       &sync.Once{},
+		closeOnce:  &sync.Once{}}
+
+	go typedSub.decode(messageSource)
+
+	return typedSub, nil
+}
+
+// NewSubscriberContext works like NewSubscriber, but the subscriber is closed as soon as the context is done.
+func NewSubscriberContext[T any](ctx context.Context, topicName string, codec Codec[T]) (*Subscriber[T], error) {
+	typedSub, err := NewSubscriber(topicName, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	go closeOnDone(ctx, typedSub.closeSink, typedSub.Close)
+	return typedSub, nil
+}
+
+func (typedSub *Subscriber[T]) decode(messageSource <-chan Message) {
+	defer close(typedSub.finished)
+
+	for {
+		var message Message
+		var ok bool
+		select {
+		case message, ok = <-messageSource:
+			if !ok {
+				return
+			}
+		case <-typedSub.done:
+			return
+		}
+
+		value, err := typedSub.codec.Decode(message.Content)
+		if err != nil {
+			select {
+			case typedSub.errorSink <- err:
+			default:
+			}
+			continue
+		}
+
+		select {
+		case typedSub.outputSink <- Typed[T]{Value: value, Timestamp: message.Timestamp}:
+		case <-typedSub.done:
+			return
+		}
+	}
+}
+
+func (typedSub *Subscriber[T]) GetOutputChannel() <-chan Typed[T] {
+	return typedSub.outputSink
+}
+
+func (typedSub *Subscriber[T]) GetErrorChannel() <-chan error {
+	return typedSub.errorSink
+}
+
+func (typedSub *Subscriber[T]) Subscriber() SubscriberIf {
+	return typedSub.sub
+}
+
+func (typedSub *Subscriber[T]) Destroy() error {
+	typedSub.once.Do(func() {
+		close(typedSub.done)
+	})
+	return typedSub.sub.Destroy()
+}
+
+// Close closes the underlying subscriber, stops decoding and closes the output channel. Decoding is stopped and
+// the output channel closed even if closing the underlying subscriber fails.
+func (typedSub *Subscriber[T]) Close() error {
+	typedSub.once.Do(func() {
+		close(typedSub.done)
+	})
+	err := typedSub.sub.Close()
+
+	<-typedSub.finished
+	typedSub.closeOnce.Do(func() {
+		close(typedSub.outputSink)
+		close(typedSub.closeSink)
+	})
+	return err
+}
+
+// StringCodec transfers plain strings in the format of eCAL's string publishers and subscribers.
+type StringCodec struct{}
+
+func (StringCodec) Encode(value string) ([]byte, error) {
+	return []byte(value), nil
+}
+
+func (StringCodec) Decode(content []byte) (string, error) {
+	return string(content), nil
+}
+
+func (StringCodec) TopicType() string {
+	return "base:std::string"
+}
@@ -0,0 +1,67 @@
+//go:build !ecalfake
+
+package ecal
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Blutkoete/golang-ecal/ecalc"
+)
+
+// ShutdownUnitName asks all processes with the given unit name to shut down.
+func ShutdownUnitName(unitName string) {
+	ecalc.ECAL_Util_ShutdownUnitName(unitName)
+}
+
+// ShutdownProcessID asks the process with the given ID to shut down.
+func ShutdownProcessID(processID int) {
+	ecalc.ECAL_Util_ShutdownProcessID(processID)
+}
+
+// ShutdownProcesses asks all eCAL processes to shut down.
+func ShutdownProcesses() {
+	ecalc.ECAL_Util_ShutdownProcesses()
+}
+
+// ShutdownCore asks the eCAL core services to shut down.
+func ShutdownCore() {
+	ecalc.ECAL_Util_ShutdownCore()
+}
+
+// TopicType returns the type of a topic registered by any publisher or subscriber. Topics become known with
+// the registration of their publishers and subscribers, which may take a moment after initialization.
+func TopicType(topicName string) (string, error) {
+	if ecalc.ECAL_IsInitialized(InitSubscriber) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitSubscriber)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	topicType := allocatedString(func(buffer uintptr, bufferLen int) int {
+		return ecalc.ECAL_Util_GetTypeName(topicName, buffer, bufferLen)
+	})
+	if topicType == "" {
+		return "", errors.New("topic type not found")
+	}
+	return topicType, nil
+}
+
+// TopicDescription returns the description of a topic registered by any publisher or subscriber.
+func TopicDescription(topicName string) (string, error) {
+	if ecalc.ECAL_IsInitialized(InitSubscriber) == 0 {
+		err := Initialize(os.Args, os.Args[0], InitSubscriber)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	topicDesc := allocatedString(func(buffer uintptr, bufferLen int) int {
+		return ecalc.ECAL_Util_GetDescription(topicName, buffer, bufferLen)
+	})
+	if topicDesc == "" {
+		return "", errors.New("topic description not found")
+	}
+	return topicDesc, nil
+}
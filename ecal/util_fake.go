@@ -0,0 +1,99 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"errors"
+	"os"
+)
+
+// TopicType returns the type of a topic from its publishers or subscribers.
+func TopicType(topicName string) (string, error) {
+	topic, ok := registry.lookup(topicName, func(topic MonitoredTopic) bool {
+		return topic.TopicType != ""
+	})
+	if !ok {
+		return "", errors.New("topic type not found")
+	}
+	return topic.TopicType, nil
+}
+
+// TopicDescription returns the description of a topic from its publishers or subscribers.
+func TopicDescription(topicName string) (string, error) {
+	topic, ok := registry.lookup(topicName, func(topic MonitoredTopic) bool {
+		return topic.TopicDescription != ""
+	})
+	if !ok {
+		return "", errors.New("topic description not found")
+	}
+	return topic.TopicDescription, nil
+}
+
+// lookup returns the first publisher or subscriber of the topic accepted by match.
+func (reg *memoryRegistry) lookup(topicName string, match func(topic MonitoredTopic) bool) (MonitoredTopic, bool) {
+	publishers := make([]*memoryPublisher, 0)
+	subscribers := make([]*memorySubscriber, 0)
+
+	reg.mutex.Lock()
+	for pub := range reg.publishers {
+		if pub.topicName == topicName {
+			publishers = append(publishers, pub)
+		}
+	}
+	for sub := range reg.subscribers {
+		if sub.topicName == topicName {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	reg.mutex.Unlock()
+
+	for _, pub := range publishers {
+		pub.mutex.Lock()
+		topic := MonitoredTopic{TopicName: pub.topicName, TopicType: pub.topicType, TopicDescription: pub.topicDesc}
+		pub.mutex.Unlock()
+		if match(topic) {
+			return topic, true
+		}
+	}
+	for _, sub := range subscribers {
+		topic := MonitoredTopic{TopicName: sub.topicName, TopicType: sub.topicType, TopicDescription: sub.topicDesc}
+		if match(topic) {
+			return topic, true
+		}
+	}
+
+	return MonitoredTopic{}, false
+}
+
+// ShutdownUnitName shuts this process down if it has the given unit name.
+func ShutdownUnitName(unitName string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.unitName == unitName {
+		registry.shutdown = true
+	}
+}
+
+// ShutdownProcessID shuts this process down if it has the given ID.
+func ShutdownProcessID(processID int) {
+	if processID != os.Getpid() {
+		return
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.shutdown = true
+}
+
+func ShutdownProcesses() {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.shutdown = true
+}
+
+// ShutdownCore does nothing, as there are no core services without eCAL.
+func ShutdownCore() {
+}
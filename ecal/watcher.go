@@ -0,0 +1,207 @@
+package ecal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	TopicAdded   = iota
+	TopicRemoved = iota
+	TopicChanged = iota
+)
+
+// topicEventBufferSize is the number of topic events kept for a slow reader. Further events are dropped, the
+// current state is always available via Topics.
+const topicEventBufferSize = 64
+
+// TopicEvent reports a publisher or subscriber that appeared, disappeared or changed its type or description.
+// Previous is only set for TopicChanged.
+type TopicEvent struct {
+	Type     int
+	Topic    MonitoredTopic
+	Previous MonitoredTopic
+}
+
+type topicKey struct {
+	hostName  string
+	processID int
+	topicID   string
+	direction string
+}
+
+// TopicWatcher polls the monitoring data and reports the changes of the publishers and subscribers.
+type TopicWatcher struct {
+	interval  time.Duration
+	topics    map[topicKey]MonitoredTopic
+	polled    bool
+	changed   chan struct{}
+	eventSink chan TopicEvent
+	errorSink chan error
+	done      chan struct{}
+	finished  chan struct{}
+	once      *sync.Once
+	mutex     *sync.Mutex
+}
+
+// NewTopicWatcher creates a watcher polling the monitoring data every interval. All topics known on the first
+// poll are reported as added.
+func NewTopicWatcher(interval time.Duration) (*TopicWatcher, error) {
+	if interval <= 0 {
+		return nil, errors.New("interval must be larger than zero")
+	}
+
+	watcher := &TopicWatcher{interval: interval,
+		topics:    make(map[topicKey]MonitoredTopic),
+		polled:    false,
+		changed:   make(chan struct{}),
+		eventSink: make(chan TopicEvent, topicEventBufferSize),
+		errorSink: make(chan error, errorBufferSize),
+		done:      make(chan struct{}),
+		finished:  make(chan struct{}),
+		once:      &sync.Once{},
+		mutex:     &sync.Mutex{}}
+
+	go watcher.watch()
+	return watcher, nil
+}
+
+func (watcher *TopicWatcher) watch() {
+	defer close(watcher.finished)
+	defer close(watcher.eventSink)
+
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+
+	for {
+		watcher.poll()
+
+		select {
+		case <-ticker.C:
+		case <-watcher.done:
+			return
+		}
+	}
+}
+
+func (watcher *TopicWatcher) poll() {
+	snapshot, err := Monitoring()
+	if err != nil {
+		select {
+		case watcher.errorSink <- err:
+		default:
+		}
+		return
+	}
+
+	topics := make(map[topicKey]MonitoredTopic, len(snapshot.Topics))
+	for _, topic := range snapshot.Topics {
+		topics[topicKey{hostName: topic.HostName,
+			processID: topic.ProcessID,
+			topicID:   topic.TopicID,
+			direction: topic.Direction}] = topic
+	}
+
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	for key, topic := range topics {
+		previous, ok := watcher.topics[key]
+		if !ok {
+			watcher.deliver(TopicEvent{Type: TopicAdded, Topic: topic})
+		} else if previous.TopicType != topic.TopicType || previous.TopicDescription != topic.TopicDescription {
+			watcher.deliver(TopicEvent{Type: TopicChanged, Topic: topic, Previous: previous})
+		}
+	}
+	for key, previous := range watcher.topics {
+		if _, ok := topics[key]; !ok {
+			watcher.deliver(TopicEvent{Type: TopicRemoved, Topic: previous})
+		}
+	}
+
+	watcher.topics = topics
+	watcher.polled = true
+	close(watcher.changed)
+	watcher.changed = make(chan struct{})
+}
+
+func (watcher *TopicWatcher) deliver(event TopicEvent) {
+	select {
+	case watcher.eventSink <- event:
+	default:
+	}
+}
+
+// GetEventChannel returns the channel reporting topic changes. Events are dropped while the channel is full.
+func (watcher *TopicWatcher) GetEventChannel() <-chan TopicEvent {
+	return watcher.eventSink
+}
+
+// GetErrorChannel returns the channel reporting failed polls. Errors are dropped while the channel is full.
+func (watcher *TopicWatcher) GetErrorChannel() <-chan error {
+	return watcher.errorSink
+}
+
+// Topics returns the publishers and subscribers found by the last poll.
+func (watcher *TopicWatcher) Topics() []MonitoredTopic {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	topics := make([]MonitoredTopic, 0, len(watcher.topics))
+	for _, topic := range watcher.topics {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// WaitForTopics blocks until every named topic has at least one publisher or subscriber in the given direction.
+// An empty direction accepts both.
+func (watcher *TopicWatcher) WaitForTopics(ctx context.Context, direction string, topicNames ...string) error {
+	for {
+		watcher.mutex.Lock()
+		present := watcher.polled && watcher.present(direction, topicNames)
+		changed := watcher.changed
+		watcher.mutex.Unlock()
+
+		if present {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-watcher.done:
+			return errors.New("topic watcher closed")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (watcher *TopicWatcher) present(direction string, topicNames []string) bool {
+	found := make(map[string]bool, len(topicNames))
+	for _, topic := range watcher.topics {
+		if direction == "" || topic.Direction == direction {
+			found[topic.TopicName] = true
+		}
+	}
+
+	for _, topicName := range topicNames {
+		if !found[topicName] {
+			return false
+		}
+	}
+	return true
+}
+
+// Close stops polling, waits for a running poll to finish and closes the event channel.
+func (watcher *TopicWatcher) Close() error {
+	err := errors.New("topic watcher already closed")
+	watcher.once.Do(func() {
+		close(watcher.done)
+		<-watcher.finished
+		err = nil
+	})
+	return err
+}
@@ -0,0 +1,97 @@
+//go:build ecalfake
+
+package ecal
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// receiveTopicEvent returns the next event of the topic, skipping the events of other topics.
+func receiveTopicEvent(t *testing.T, watcher *TopicWatcher, topicName string) TopicEvent {
+	t.Helper()
+
+	timeout := time.After(testTimeout)
+	for {
+		select {
+		case event, ok := <-watcher.GetEventChannel():
+			if !ok {
+				t.Fatal("event channel closed")
+			}
+			if event.Topic.TopicName == topicName {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("no event received for %s", topicName)
+		}
+	}
+}
+
+func TestTopicWatcher(t *testing.T) {
+	if _, err := NewTopicWatcher(0); err == nil {
+		t.Error("watcher created without interval")
+	}
+
+	watcher, err := NewTopicWatcher(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, _, err := PublisherCreate("watcher_topic", "base:std::string", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	err = watcher.WaitForTopics(ctx, TopicDirectionPublisher, "watcher_topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := receiveTopicEvent(t, watcher, "watcher_topic")
+	if event.Type != TopicAdded || event.Topic.Direction != TopicDirectionPublisher ||
+		event.Topic.TopicType != "base:std::string" {
+		t.Errorf("received %+v, want an added publisher", event)
+	}
+
+	err = pub.SetDescription("changed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	event = receiveTopicEvent(t, watcher, "watcher_topic")
+	if event.Type != TopicChanged || event.Topic.TopicDescription != "changed" ||
+		event.Previous.TopicDescription != "" {
+		t.Errorf("received %+v, want a changed description", event)
+	}
+
+	err = pub.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	event = receiveTopicEvent(t, watcher, "watcher_topic")
+	if event.Type != TopicRemoved || event.Topic.TopicDescription != "changed" {
+		t.Errorf("received %+v, want a removed publisher", event)
+	}
+
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer shortCancel()
+	err = watcher.WaitForTopics(shortCtx, "", "watcher_topic")
+	if err != context.DeadlineExceeded {
+		t.Errorf("waiting for a removed topic returned %v", err)
+	}
+
+	err = watcher.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range watcher.GetEventChannel() {
+	}
+	if watcher.Close() == nil {
+		t.Error("second Close succeeded")
+	}
+	if watcher.WaitForTopics(context.Background(), "", "watcher_topic") == nil {
+		t.Error("waiting on a closed watcher succeeded")
+	}
+}
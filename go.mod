@@ -1,6 +1,6 @@
 module github.com/Blutkoete/golang-ecal
 
-go 1.14
+go 1.21
 
 require (
 	github.com/golang/protobuf v1.4.1
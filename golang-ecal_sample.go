@@ -28,7 +28,7 @@ func minimalSnd() {
 			log.Printf("Sent \"%s\"\n", message.Content)
 		case <-time.After(time.Second):
 		}
-		<-time.After(250 * time.Millisecond)
+		ecal.ECALClock.Sleep(250 * time.Millisecond)
 	}
 }
 
@@ -49,7 +49,12 @@ func minimalRec() {
 }
 
 func personSnd() {
-	pub, pubChannel, err := ecal.PublisherCreate("person", "proto:pb.People.Person", "", true)
+	topicDesc, err := ecal.ProtoTopicDescription(&pbexample.Person{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pub, pubChannel, err := ecal.PublisherCreate("person", ecal.ProtoTopicType(&pbexample.Person{}), topicDesc, true)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -74,12 +79,12 @@ func personSnd() {
 			log.Printf("Sent \"%s\"\n", person)
 		case <-time.After(time.Second):
 		}
-		<-time.After(250 * time.Millisecond)
+		ecal.ECALClock.Sleep(250 * time.Millisecond)
 	}
 }
 
 func personRec() {
-	sub, subChannel, err := ecal.SubscriberCreate("person", "proto:pb.People.Person", "", true, 1024)
+	sub, subChannel, err := ecal.SubscriberCreate("person", ecal.ProtoTopicType(&pbexample.Person{}), "", true, 1024)
 	if err != nil {
 		log.Fatal(err)
 	}